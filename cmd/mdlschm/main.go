@@ -0,0 +1,190 @@
+// Command mdlschm works with Terraform schemas from the output of
+// `terraform providers schema -json`.
+//
+// Usage:
+//
+//	mdlschm docs -schema schema.json -type aws_widget
+//	mdlschm model -schema schema.json -type aws_widget -name widgetModel
+//	mdlschm diff -old old.json -new new.json -type aws_widget
+//
+// The JSON schema has no validators or plan modifiers, so docs cannot list
+// valid values, ranges, defaults or which changes force a new resource, and
+// diff cannot see new replace plan modifiers. Use mdlschm.Docs and
+// mdlschm.DiffModels on the Go models for those.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/YakDriver/mdlschm"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+const usage = `usage: mdlschm <command> [flags]
+
+commands:
+  docs    render registry-style Markdown docs for a resource or data source;
+          the JSON has no validators or plan modifiers, so valid values,
+          ranges, defaults and replacements are not documented (use
+          mdlschm.Docs on the Go model for those)
+  model   generate a tagged Go model struct for a resource or data source
+  diff    classify the changes between two versions of a resource or data
+          source, failing if state changes without a version bump; the JSON
+          has no plan modifiers, so new replace plan modifiers are not
+          found (use mdlschm.DiffModels on the Go models for those)
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "docs":
+		err = docs(os.Args[2:])
+	case "model":
+		err = model(os.Args[2:])
+	case "diff":
+		err = diff(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mdlschm %s: %s\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func docs(args []string) error {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	schemaFile := fs.String("schema", "-", "output of `terraform providers schema -json` (- for stdin)")
+	typeName := fs.String("type", "", "resource or data source type name, eg, aws_widget")
+	fs.Parse(args)
+
+	if *typeName == "" {
+		return fmt.Errorf("-type is required")
+	}
+
+	data, err := readFile(*schemaFile)
+	if err != nil {
+		return err
+	}
+
+	schm, diags := mdlschm.SchemaFromJSON(data, *typeName)
+	if err := diagsError(diags); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "warning: the JSON schema has no validators or plan modifiers, so valid values, ranges, defaults and replacements are not documented; use mdlschm.Docs on the Go model to include them")
+
+	fmt.Println(mdlschm.DocsFromSchema(schm))
+
+	return nil
+}
+
+func model(args []string) error {
+	fs := flag.NewFlagSet("model", flag.ExitOnError)
+	schemaFile := fs.String("schema", "-", "output of `terraform providers schema -json` (- for stdin)")
+	typeName := fs.String("type", "", "resource or data source type name, eg, aws_widget")
+	name := fs.String("name", "model", "name of the generated Go struct type")
+	fs.Parse(args)
+
+	if *typeName == "" {
+		return fmt.Errorf("-type is required")
+	}
+
+	data, err := readFile(*schemaFile)
+	if err != nil {
+		return err
+	}
+
+	schm, diags := mdlschm.SchemaFromJSON(data, *typeName)
+	if err := diagsError(diags); err != nil {
+		return err
+	}
+
+	src, diags := mdlschm.GenerateModel(schm, *name)
+	if err := diagsError(diags); err != nil {
+		return err
+	}
+
+	fmt.Print(src)
+
+	return nil
+}
+
+func diff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	oldFile := fs.String("old", "", "output of `terraform providers schema -json` before the change")
+	newFile := fs.String("new", "-", "output of `terraform providers schema -json` after the change (- for stdin)")
+	typeName := fs.String("type", "", "resource or data source type name, eg, aws_widget")
+	fs.Parse(args)
+
+	if *typeName == "" {
+		return fmt.Errorf("-type is required")
+	}
+
+	if *oldFile == "" {
+		return fmt.Errorf("-old is required")
+	}
+
+	schms := []tfsdk.Schema{}
+
+	for _, name := range []string{*oldFile, *newFile} {
+		data, err := readFile(name)
+		if err != nil {
+			return err
+		}
+
+		schm, diags := mdlschm.SchemaFromJSON(data, *typeName)
+		if err := diagsError(diags); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		schms = append(schms, schm)
+	}
+
+	fmt.Fprintln(os.Stderr, "warning: the JSON schema has no plan modifiers, so new replace plan modifiers are not reported; use mdlschm.DiffModels on the Go models to find them")
+
+	changes, diags := mdlschm.Diff(schms[0], schms[1])
+
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+
+	return diagsError(diags)
+}
+
+func readFile(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	return os.ReadFile(name)
+}
+
+// diagsError prints warnings and returns the errors, if any, as one error.
+func diagsError(diags diag.Diagnostics) error {
+	for _, d := range diags.Warnings() {
+		fmt.Fprintf(os.Stderr, "warning: %s: %s\n", d.Summary(), d.Detail())
+	}
+
+	if !diags.HasError() {
+		return nil
+	}
+
+	for _, d := range diags.Errors() {
+		fmt.Fprintf(os.Stderr, "error: %s: %s\n", d.Summary(), d.Detail())
+	}
+
+	return fmt.Errorf("%d error(s)", len(diags.Errors()))
+}
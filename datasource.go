@@ -0,0 +1,240 @@
+package mdlschm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	// Tag keys
+	TagDataSource = "ds"
+
+	// Tag Values
+	TagDataSourceComputed = "computed"
+	TagDataSourceOmit     = "omit"
+	TagDataSourceOptional = "optional"
+	TagDataSourceRequired = "required"
+)
+
+// DataSourceFrom converts a resource model struct into a data source
+// tfsdk.Schema. Everything is computed except lookupFields, the attribute
+// names of the top-level fields used to find the data source, which are
+// required. Plan modifiers, including defaults, are dropped since they mean
+// nothing to data sources, while descriptions are kept. The ds tag
+// overrides a top-level field: required, optional, computed or omit.
+// DataSourceFrom panics if the model has any problems. Use DataSourceFromE
+// to get them as diagnostics instead.
+func DataSourceFrom(model any, lookupFields ...string) tfsdk.Schema {
+	schm, diags := DataSourceFromE(model, lookupFields...)
+
+	if diags.HasError() {
+		msgs := []string{}
+		for _, d := range diags.Errors() {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", d.Summary(), d.Detail()))
+		}
+		panic(strings.Join(msgs, "; "))
+	}
+
+	return schm
+}
+
+// DataSourceFromE works like DataSourceFrom but returns diagnostics for every
+// problem found in the model rather than panicking.
+func DataSourceFromE(model any, lookupFields ...string) (tfsdk.Schema, diag.Diagnostics) {
+	schm, diags := NewE(model)
+	if diags.HasError() {
+		return tfsdk.Schema{}, diags
+	}
+
+	schm.Version = 0 // data sources have no state to upgrade
+
+	modes := dataSourceModes(model, lookupFields, &diags)
+
+	for k, a := range schm.Attributes {
+		switch modes[k] {
+		case TagDataSourceOmit:
+			delete(schm.Attributes, k)
+		case TagDataSourceRequired, TagDataSourceOptional:
+			a.Required = modes[k] == TagDataSourceRequired
+			a.Optional = modes[k] == TagDataSourceOptional
+			a.Computed = modes[k] == TagDataSourceOptional
+			schm.Attributes[k] = lookupAttribute(a)
+		default:
+			schm.Attributes[k] = computedAttribute(a)
+		}
+	}
+
+	timeouts := timeoutsNames(model)
+
+	for k, b := range schm.Blocks {
+		// data sources only read, however long the resource takes otherwise
+		if timeouts[k] && modes[k] != TagDataSourceOmit {
+			tb := timeoutsBlock([]string{TagTimeoutsRead})
+			tb.DeprecationMessage = b.DeprecationMessage
+			tb.Description = b.Description
+			tb.MarkdownDescription = b.MarkdownDescription
+			schm.Blocks[k] = *tb
+			continue
+		}
+
+		switch modes[k] {
+		case TagDataSourceOmit:
+			delete(schm.Blocks, k)
+		case TagDataSourceRequired, TagDataSourceOptional:
+			schm.Blocks[k] = lookupBlock(b)
+		default:
+			// blocks cannot be computed so they become computed attributes
+			// of the same type
+			if schm.Attributes == nil {
+				schm.Attributes = make(map[string]tfsdk.Attribute)
+			}
+			schm.Attributes[k] = tfsdk.Attribute{
+				Type:                b.Type(),
+				Computed:            true,
+				DeprecationMessage:  b.DeprecationMessage,
+				Description:         b.Description,
+				MarkdownDescription: b.MarkdownDescription,
+			}
+			delete(schm.Blocks, k)
+		}
+	}
+
+	if len(schm.Attributes) == 0 {
+		schm.Attributes = nil
+	}
+
+	if len(schm.Blocks) == 0 {
+		schm.Blocks = nil
+	}
+
+	return schm, diags
+}
+
+// dataSourceModes returns the data source mode (required, optional, computed
+// or omit) for each top-level attribute name that is not simply computed.
+func dataSourceModes(model any, lookupFields []string, diags *diag.Diagnostics) map[string]string {
+	modes := make(map[string]string)
+
+	for _, l := range lookupFields {
+		modes[l] = TagDataSourceRequired
+	}
+
+	found := make(map[string]bool)
+
+	for _, f := range modelFields(reflect.TypeOf(model)) {
+		tags := string(f.Tag)
+
+		// problems with names were already reported by NewE
+		s := attrName(f.Name, tags, f.Name, &diag.Diagnostics{})
+		if s == "" {
+			continue
+		}
+
+		found[s] = true
+
+		switch v := tagValue(TagDataSource, tags); v {
+		case "":
+		case TagDataSourceComputed, TagDataSourceOmit, TagDataSourceOptional, TagDataSourceRequired:
+			modes[s] = v
+		default:
+			addTagError(diags, f.fieldPath, TagDataSource, fmt.Sprintf("unrecognized data source mode: %s", v))
+		}
+	}
+
+	for _, l := range lookupFields {
+		if !found[l] {
+			diags.AddError("Invalid lookup field", fmt.Sprintf("lookup field %s is not in the model", l))
+		}
+	}
+
+	return modes
+}
+
+// timeoutsNames returns the attribute names of the top-level Timeouts fields
+// of a model.
+func timeoutsNames(model any) map[string]bool {
+	names := make(map[string]bool)
+
+	for _, f := range modelFields(reflect.TypeOf(model)) {
+		if !isTimeouts(f.Type) {
+			continue
+		}
+
+		// problems with names were already reported by NewE
+		if s := attrName(f.Name, string(f.Tag), f.Name, &diag.Diagnostics{}); s != "" {
+			names[s] = true
+		}
+	}
+
+	return names
+}
+
+// computedAttribute returns a read-only copy of an attribute. Nested
+// attributes become computed attributes of the same type.
+func computedAttribute(a tfsdk.Attribute) tfsdk.Attribute {
+	return tfsdk.Attribute{
+		Type:                a.FrameworkType(),
+		Computed:            true,
+		Sensitive:           a.Sensitive,
+		DeprecationMessage:  a.DeprecationMessage,
+		Description:         a.Description,
+		MarkdownDescription: a.MarkdownDescription,
+	}
+}
+
+// lookupAttribute returns a copy of an attribute, and its nested attributes,
+// without plan modifiers.
+func lookupAttribute(a tfsdk.Attribute) tfsdk.Attribute {
+	a.PlanModifiers = nil
+
+	if a.Attributes == nil {
+		return a
+	}
+
+	attrs := make(map[string]tfsdk.Attribute)
+	for k, na := range nestedAttributes(a) {
+		attrs[k] = lookupAttribute(na)
+	}
+
+	switch a.FrameworkType().(type) {
+	case types.ListType:
+		a.Attributes = tfsdk.ListNestedAttributes(attrs)
+	case types.SetType:
+		a.Attributes = tfsdk.SetNestedAttributes(attrs)
+	case types.MapType:
+		a.Attributes = tfsdk.MapNestedAttributes(attrs)
+	default:
+		a.Attributes = tfsdk.SingleNestedAttributes(attrs)
+	}
+
+	return a
+}
+
+// lookupBlock returns a copy of a block, and its nested attributes and
+// blocks, without plan modifiers.
+func lookupBlock(b tfsdk.Block) tfsdk.Block {
+	b.PlanModifiers = nil
+
+	if len(b.Attributes) > 0 {
+		attrs := make(map[string]tfsdk.Attribute)
+		for k, a := range b.Attributes {
+			attrs[k] = lookupAttribute(a)
+		}
+		b.Attributes = attrs
+	}
+
+	if len(b.Blocks) > 0 {
+		blocks := make(map[string]tfsdk.Block)
+		for k, nb := range b.Blocks {
+			blocks[k] = lookupBlock(nb)
+		}
+		b.Blocks = blocks
+	}
+
+	return b
+}
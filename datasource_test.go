@@ -0,0 +1,193 @@
+package mdlschm
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDataSourceFrom(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		model        any
+		lookupFields []string
+		want         tfsdk.Schema
+	}{
+		"Basic": {
+			model: struct {
+				_        struct{}     `version:"2" desc:"Manages a widget"`
+				Name     types.String `tfsdk:"name" required:"true" pmods:"replace" valid:"between(1,64)" desc:"Name of the widget"`
+				ID       types.String `tfsdk:"id" computed:"true" pmods:"usfu"`
+				Size     types.Int64  `tfsdk:"size" optional:"true" computed:"true" pmods:"default(3)"`
+				Region   types.String `tfsdk:"region" optional:"true" ds:"optional"`
+				Password types.String `tfsdk:"password" required:"true" sensitive:"true" ds:"omit"`
+				Rules    []struct {
+					Port types.Int64 `tfsdk:"port" required:"true"`
+				} `tfsdk:"rules" desc:"Rules for the widget"`
+			}{},
+			lookupFields: []string{"name"},
+			want: tfsdk.Schema{
+				Description: "Manages a widget",
+				Attributes: map[string]tfsdk.Attribute{
+					"name": {
+						Type:        types.StringType,
+						Required:    true,
+						Description: "Name of the widget",
+						Validators: []tfsdk.AttributeValidator{
+							stringvalidator.LengthBetween(1, 64),
+						},
+					},
+					"id": {
+						Type:     types.StringType,
+						Computed: true,
+					},
+					"size": {
+						Type:     types.Int64Type,
+						Computed: true,
+					},
+					"region": {
+						Type:     types.StringType,
+						Optional: true,
+						Computed: true,
+					},
+					"rules": {
+						Type: types.ListType{
+							ElemType: types.ObjectType{
+								AttrTypes: map[string]attr.Type{
+									"port": types.Int64Type,
+								},
+							},
+						},
+						Computed:    true,
+						Description: "Rules for the widget",
+					},
+				},
+			},
+		},
+		"RequiredBlock": {
+			model: struct {
+				Filter []struct {
+					Name   types.String `tfsdk:"name" required:"true" pmods:"replace"`
+					Values []string     `tfsdk:"values" required:"true"`
+				} `tfsdk:"filter" ds:"required"`
+			}{},
+			want: tfsdk.Schema{
+				Blocks: map[string]tfsdk.Block{
+					"filter": {
+						Attributes: map[string]tfsdk.Attribute{
+							"name": {
+								Type:     types.StringType,
+								Required: true,
+							},
+							"values": {
+								Type: types.ListType{
+									ElemType: types.StringType,
+								},
+								Required: true,
+							},
+						},
+						NestingMode: tfsdk.BlockNestingModeList,
+					},
+				},
+			},
+		},
+		"RequiredNestedAttribute": {
+			model: struct {
+				Filter []struct {
+					Name   types.String `tfsdk:"name" required:"true" pmods:"replace"`
+					Values []string     `tfsdk:"values" optional:"true" computed:"true" pmods:"usfu"`
+				} `tfsdk:"filter" nesting:"attribute" required:"true" pmods:"usfu" ds:"required"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"filter": {
+						Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+							"name": {
+								Type:     types.StringType,
+								Required: true,
+							},
+							"values": {
+								Type: types.ListType{
+									ElemType: types.StringType,
+								},
+								Optional: true,
+								Computed: true,
+							},
+						}),
+						Required: true,
+					},
+				},
+			},
+		},
+		"Timeouts": {
+			model: struct {
+				Name     types.String `tfsdk:"name" required:"true"`
+				Timeouts *Timeouts    `tfsdk:"timeouts" timeouts:"create,update,delete"`
+			}{},
+			lookupFields: []string{"name"},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"name": {
+						Type:     types.StringType,
+						Required: true,
+					},
+				},
+				Blocks: map[string]tfsdk.Block{
+					"timeouts": {
+						Attributes: map[string]tfsdk.Attribute{
+							"read": {
+								Type:     types.StringType,
+								Optional: true,
+								Validators: []tfsdk.AttributeValidator{
+									StringIsDuration(),
+								},
+							},
+						},
+						NestingMode: tfsdk.BlockNestingModeSingle,
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := DataSourceFrom(test.model, test.lookupFields...)
+
+			if diff := deep.Equal(got, test.want); diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, test.want, diff)
+			}
+		})
+	}
+}
+
+func TestDataSourceFromE(t *testing.T) {
+	t.Parallel()
+
+	_, diags := DataSourceFromE(struct {
+		Name types.String `tfsdk:"name" ds:"lookup"`
+	}{}, "arn")
+
+	got := []string{}
+	for _, d := range diags.Errors() {
+		got = append(got, d.Summary())
+	}
+
+	want := []string{
+		"Invalid ds tag on field Name",
+		"Invalid lookup field",
+	}
+
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, want, diff)
+	}
+}
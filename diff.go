@@ -0,0 +1,416 @@
+package mdlschm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ChangeKind is how a schema change affects the users of a resource.
+type ChangeKind int
+
+const (
+	// ChangeCompatible needs nothing from users, eg, a new optional
+	// attribute.
+	ChangeCompatible ChangeKind = iota
+
+	// ChangeStateUpgrade alters stored state without affecting
+	// configurations, eg, removing a computed attribute, so needs a state
+	// upgrader.
+	ChangeStateUpgrade
+
+	// ChangeBreaking can break existing configurations or replace existing
+	// resources, eg, a new required attribute.
+	ChangeBreaking
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeCompatible:
+		return "compatible"
+	case ChangeStateUpgrade:
+		return "requires state upgrade"
+	case ChangeBreaking:
+		return "breaking"
+	}
+
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is one difference between two schemas.
+type Change struct {
+	// Path is the attribute or block changed, empty for the schema itself.
+	Path path.Path
+
+	Kind ChangeKind
+
+	// AffectsState reports whether the change alters the shape of stored
+	// state, eg, a type change, which needs a schema version bump, whatever
+	// its Kind.
+	AffectsState bool
+
+	Detail string
+}
+
+func (c Change) String() string {
+	if len(c.Path.Steps()) == 0 {
+		return fmt.Sprintf("%s: %s", c.Kind, c.Detail)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", c.Kind, c.Path, c.Detail)
+}
+
+// Diff compares two versions of a schema, eg, before and after editing its
+// model, and returns each change, ordered by path, classified as
+// compatible, requiring a state upgrade or breaking. Breaking changes
+// include removing attributes and blocks, changing types and nesting modes,
+// making attributes and blocks required, allowing fewer block items and
+// adding replace plan modifiers. Diff
+// returns an error if any change affects state and the new schema's Version,
+// from the version tag on _, is not greater than the old one's.
+func Diff(old, new tfsdk.Schema) ([]Change, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	changes := []Change{}
+
+	diffAttributes(old.Attributes, new.Attributes, path.Empty(), &changes)
+	diffBlocks(old.Blocks, new.Blocks, path.Empty(), &changes)
+
+	if old.DeprecationMessage == "" && new.DeprecationMessage != "" {
+		changes = append(changes, Change{Kind: ChangeCompatible, Detail: "deprecated"})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path.String() < changes[j].Path.String()
+	})
+
+	if new.Version < old.Version {
+		diags.AddError("Invalid schema version", fmt.Sprintf("version went down from %d to %d", old.Version, new.Version))
+		return changes, diags
+	}
+
+	if new.Version == old.Version {
+		for _, c := range changes {
+			if c.AffectsState {
+				diags.AddError("Schema version not incremented", fmt.Sprintf("%s, %s, alters stored state, so increment the version tag on _ from %d and add a state upgrader", c.Path, c.Detail, old.Version))
+				break
+			}
+		}
+	}
+
+	return changes, diags
+}
+
+// DiffModels works like Diff on the schemas of two versions of a model, eg,
+// Diff(New(old), New(new)), but reports problems with the models as
+// diagnostics. Unlike schemas from `terraform providers schema -json`,
+// models have plan modifiers, so DiffModels can find new replace plan
+// modifiers.
+func DiffModels(old, new any) ([]Change, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	oldSchm, d := NewE(old)
+	diags.Append(d...)
+
+	newSchm, d := NewE(new)
+	diags.Append(d...)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	changes, d := Diff(oldSchm, newSchm)
+	diags.Append(d...)
+
+	return changes, diags
+}
+
+// diffAttributes adds the changes between two sets of attributes.
+func diffAttributes(old, new map[string]tfsdk.Attribute, p path.Path, changes *[]Change) {
+	for name, o := range old {
+		ap := p.AtName(name)
+
+		n, ok := new[name]
+		if !ok {
+			kind := ChangeBreaking
+			if !o.Required && !o.Optional {
+				kind = ChangeStateUpgrade
+			}
+			*changes = append(*changes, Change{Path: ap, Kind: kind, AffectsState: true, Detail: "attribute removed"})
+			continue
+		}
+
+		diffAttribute(o, n, ap, changes)
+	}
+
+	for name, n := range new {
+		if _, ok := old[name]; ok {
+			continue
+		}
+
+		kind := ChangeCompatible
+		if n.Required {
+			kind = ChangeBreaking
+		}
+		*changes = append(*changes, Change{Path: p.AtName(name), Kind: kind, Detail: fmt.Sprintf("%s attribute added", attrRole(n))})
+	}
+}
+
+// diffAttribute adds the changes to an attribute.
+func diffAttribute(o, n tfsdk.Attribute, p path.Path, changes *[]Change) {
+	ctx := context.Background()
+
+	add := func(kind ChangeKind, affectsState bool, format string, a ...any) {
+		*changes = append(*changes, Change{Path: p, Kind: kind, AffectsState: affectsState, Detail: fmt.Sprintf(format, a...)})
+	}
+
+	ot, nt := o.FrameworkType(), n.FrameworkType()
+
+	switch {
+	case o.Attributes != nil && n.Attributes != nil && nestingText(ot) == nestingText(nt):
+		diffAttributes(nestedAttributes(o), nestedAttributes(n), p, changes)
+	case o.Attributes != nil && n.Attributes != nil:
+		add(typeChangeKind(o), true, "nesting mode changed from %s to %s", nestingText(ot), nestingText(nt))
+	case !ot.TerraformType(ctx).Equal(nt.TerraformType(ctx)):
+		add(typeChangeKind(o), true, "type changed from %s to %s", typeText(o), typeText(n))
+	case !ot.Equal(nt):
+		// eg, types.Int64Type and types.NumberType, which state stores alike
+		add(ChangeCompatible, false, "type changed from %s to %s", typeText(o), typeText(n))
+	}
+
+	if or, nr := attrRole(o), attrRole(n); or != nr {
+		kind := ChangeCompatible
+		switch {
+		case n.Required && !o.Required:
+			// configurations without it are now invalid
+			kind = ChangeBreaking
+		case !n.Required && !n.Optional && (o.Required || o.Optional):
+			// configurations with it are now invalid
+			kind = ChangeBreaking
+		}
+		add(kind, false, "changed from %s to %s", or, nr)
+	}
+
+	if !o.Sensitive && n.Sensitive {
+		add(ChangeCompatible, false, "now sensitive")
+	}
+
+	if o.DeprecationMessage == "" && n.DeprecationMessage != "" {
+		add(ChangeCompatible, false, "deprecated")
+	}
+
+	for _, desc := range newReplaceModifiers(o.PlanModifiers, n.PlanModifiers) {
+		add(ChangeBreaking, false, "replace plan modifier added: %s", desc)
+	}
+}
+
+// diffBlocks adds the changes between two sets of blocks.
+func diffBlocks(old, new map[string]tfsdk.Block, p path.Path, changes *[]Change) {
+	for name, o := range old {
+		bp := p.AtName(name)
+
+		n, ok := new[name]
+		if !ok {
+			*changes = append(*changes, Change{Path: bp, Kind: ChangeBreaking, AffectsState: true, Detail: "block removed"})
+			continue
+		}
+
+		if o.NestingMode != n.NestingMode {
+			*changes = append(*changes, Change{Path: bp, Kind: ChangeBreaking, AffectsState: true, Detail: fmt.Sprintf("nesting mode changed from %s to %s", blockNestingText(o), blockNestingText(n))})
+			continue
+		}
+
+		if o.DeprecationMessage == "" && n.DeprecationMessage != "" {
+			*changes = append(*changes, Change{Path: bp, Kind: ChangeCompatible, Detail: "deprecated"})
+		}
+
+		for _, desc := range newReplaceModifiers(o.PlanModifiers, n.PlanModifiers) {
+			*changes = append(*changes, Change{Path: bp, Kind: ChangeBreaking, Detail: fmt.Sprintf("replace plan modifier added: %s", desc)})
+		}
+
+		diffBlockSize(o, n, bp, changes)
+
+		diffAttributes(o.Attributes, n.Attributes, bp, changes)
+		diffBlocks(o.Blocks, n.Blocks, bp, changes)
+	}
+
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			*changes = append(*changes, Change{Path: p.AtName(name), Kind: ChangeCompatible, Detail: "block added"})
+		}
+	}
+}
+
+// diffBlockSize adds the changes to the number of items a block allows.
+// Configurations with fewer or more items than before may now be invalid.
+func diffBlockSize(o, n tfsdk.Block, p path.Path, changes *[]Change) {
+	add := func(kind ChangeKind, format string, a ...any) {
+		*changes = append(*changes, Change{Path: p, Kind: kind, Detail: fmt.Sprintf(format, a...)})
+	}
+
+	omin, omax := blockSize(o)
+	nmin, nmax := blockSize(n)
+
+	switch {
+	case omin == 0 && nmin > 0:
+		add(ChangeBreaking, "changed from optional to required")
+	case omin > 0 && nmin == 0:
+		add(ChangeCompatible, "changed from required to optional")
+	case nmin > omin:
+		add(ChangeBreaking, "min items increased from %d to %d", omin, nmin)
+	case nmin < omin:
+		add(ChangeCompatible, "min items decreased from %d to %d", omin, nmin)
+	}
+
+	switch {
+	case nmax != 0 && (omax == 0 || nmax < omax):
+		add(ChangeBreaking, "max items decreased from %s to %d", maxItemsText(omax), nmax)
+	case omax != 0 && (nmax == 0 || nmax > omax):
+		add(ChangeCompatible, "max items increased from %d to %s", omax, maxItemsText(nmax))
+	}
+}
+
+// blockSize returns the fewest and most items a list or set block allows,
+// from MinItems, MaxItems and its size validators, with 0 for no most.
+func blockSize(b tfsdk.Block) (int64, int64) {
+	ctx := context.Background()
+
+	min, max := b.MinItems, b.MaxItems
+
+	atLeast := func(n int64) {
+		if n > min {
+			min = n
+		}
+	}
+
+	atMost := func(n int64) {
+		if max == 0 || n < max {
+			max = n
+		}
+	}
+
+	for _, v := range b.Validators {
+		desc := v.Description(ctx)
+
+		for _, kind := range []string{"list", "set"} {
+			var lo, hi int64
+
+			// Sscanf ignores trailing text, so the longest format goes first
+			if n, _ := fmt.Sscanf(desc, kind+" must contain at least %d elements and at most %d elements", &lo, &hi); n == 2 {
+				atLeast(lo)
+				atMost(hi)
+			} else if n, _ := fmt.Sscanf(desc, kind+" must contain at least %d elements", &lo); n == 1 {
+				atLeast(lo)
+			} else if n, _ := fmt.Sscanf(desc, kind+" must contain at most %d elements", &hi); n == 1 {
+				atMost(hi)
+			}
+		}
+	}
+
+	return min, max
+}
+
+// maxItemsText returns the most items a block allows for change details.
+func maxItemsText(max int64) string {
+	if max == 0 {
+		return "unlimited"
+	}
+
+	return fmt.Sprint(max)
+}
+
+// typeChangeKind returns the kind of a change to the type of attribute a,
+// which only breaks configurations that can set it.
+func typeChangeKind(a tfsdk.Attribute) ChangeKind {
+	if a.Required || a.Optional {
+		return ChangeBreaking
+	}
+
+	return ChangeStateUpgrade
+}
+
+// attrRole returns how an attribute is set, eg, optional and computed.
+func attrRole(a tfsdk.Attribute) string {
+	switch {
+	case a.Required:
+		return "required"
+	case a.Optional && a.Computed:
+		return "optional and computed"
+	case a.Optional:
+		return "optional"
+	}
+
+	return "computed"
+}
+
+// typeText returns the type of an attribute for change details.
+func typeText(a tfsdk.Attribute) string {
+	if a.Attributes != nil {
+		return fmt.Sprintf("%s nested attributes", nestingText(a.FrameworkType()))
+	}
+
+	return a.Type.String()
+}
+
+// nestingText returns the nesting mode of a nested attribute's type.
+func nestingText(t attr.Type) string {
+	switch t.(type) {
+	case types.ListType:
+		return "list"
+	case types.SetType:
+		return "set"
+	case types.MapType:
+		return "map"
+	}
+
+	return "single"
+}
+
+// blockNestingText returns the nesting mode of a block.
+func blockNestingText(b tfsdk.Block) string {
+	switch b.NestingMode {
+	case tfsdk.BlockNestingModeList:
+		return "list"
+	case tfsdk.BlockNestingModeSet:
+		return "set"
+	case tfsdk.BlockNestingModeSingle:
+		return "single"
+	}
+
+	return "unknown"
+}
+
+// newReplaceModifiers returns the descriptions of replace plan modifiers,
+// conditional or not, in new that are not in old.
+func newReplaceModifiers(old, new tfsdk.AttributePlanModifiers) []string {
+	ctx := context.Background()
+
+	replaceTypes := map[reflect.Type]bool{
+		reflect.TypeOf(resource.RequiresReplace()):              true,
+		reflect.TypeOf(resource.RequiresReplaceIf(nil, "", "")): true,
+	}
+
+	had := make(map[string]bool)
+	for _, pm := range old {
+		if replaceTypes[reflect.TypeOf(pm)] {
+			had[pm.Description(ctx)] = true
+		}
+	}
+
+	added := []string{}
+	for _, pm := range new {
+		if replaceTypes[reflect.TypeOf(pm)] && !had[pm.Description(ctx)] {
+			added = append(added, pm.Description(ctx))
+		}
+	}
+
+	return added
+}
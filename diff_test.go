@@ -0,0 +1,215 @@
+package mdlschm
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type testDiffModel struct {
+	_        struct{}     `version:"1"`
+	ID       types.String `computed:"true"`
+	Name     types.String `required:"true"`
+	Size     types.Int64  `optional:"true"`
+	Zone     types.String `optional:"true"`
+	Endpoint []struct {
+		Port types.Int64 `required:"true"`
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		new        any
+		want       []string
+		wantErrors []string
+	}{
+		"Same": {
+			new:  testDiffModel{},
+			want: []string{},
+		},
+		"Compatible": {
+			new: struct {
+				_        struct{}     `version:"1"`
+				ID       types.String `computed:"true"`
+				Name     types.String `optional:"true" desc:"Name of the widget" sensitive:"true"`
+				Size     types.Number `optional:"true"`
+				Zone     types.String `optional:"true" computed:"true"`
+				Owner    types.String `optional:"true"`
+				Endpoint []struct {
+					Port types.Int64 `required:"true"`
+				}
+				Rule []struct {
+					Action types.String `required:"true"`
+				}
+			}{},
+			want: []string{
+				"compatible: name: changed from required to optional",
+				"compatible: name: now sensitive",
+				"compatible: owner: optional attribute added",
+				"compatible: rule: block added",
+				"compatible: size: type changed from types.Int64Type to types.NumberType",
+				"compatible: zone: changed from optional to optional and computed",
+			},
+		},
+		"Breaking": {
+			new: struct {
+				_        struct{}     `version:"2"`
+				ID       types.String `computed:"true"`
+				Name     types.String `required:"true" pmods:"replace"`
+				Size     types.String `optional:"true"`
+				Zone     types.String `required:"true"`
+				Region   types.String `required:"true"`
+				Endpoint struct {
+					Port types.Int64 `required:"true"`
+				} `collection:"single"`
+			}{},
+			want: []string{
+				"breaking: endpoint: nesting mode changed from list to single",
+				"breaking: name: replace plan modifier added: If the value of this attribute changes, Terraform will destroy and recreate the resource.",
+				"breaking: region: required attribute added",
+				"breaking: size: type changed from types.Int64Type to types.StringType",
+				"breaking: zone: changed from optional to required",
+			},
+		},
+		"StateUpgrade": {
+			new: struct {
+				_        struct{}     `version:"2"`
+				Name     types.String `required:"true"`
+				Size     types.Int64  `optional:"true"`
+				Zone     types.String `optional:"true"`
+				Endpoint []struct {
+					Port types.Int64 `required:"true" pmods:"replace(ifset)"`
+					Host types.String
+				}
+			}{},
+			want: []string{
+				"compatible: endpoint.host: optional attribute added",
+				"breaking: endpoint.port: replace plan modifier added: If the value of this attribute changes after it is set, Terraform will destroy and recreate the resource.",
+				"requires state upgrade: id: attribute removed",
+			},
+		},
+		"BlockSize": {
+			new: struct {
+				_        struct{}     `version:"1"`
+				ID       types.String `computed:"true"`
+				Name     types.String `required:"true"`
+				Size     types.Int64  `optional:"true"`
+				Zone     types.String `optional:"true"`
+				Endpoint []struct {
+					Port types.Int64 `required:"true"`
+				} `valid:"between(1,3)"`
+			}{},
+			want: []string{
+				"breaking: endpoint: changed from optional to required",
+				"breaking: endpoint: max items decreased from unlimited to 3",
+			},
+		},
+		"NoVersionBump": {
+			new: struct {
+				_        struct{}     `version:"1"`
+				Name     types.String `required:"true"`
+				Size     types.Int64  `optional:"true"`
+				Zone     types.String `optional:"true"`
+				Endpoint []struct {
+					Port types.String `required:"true"`
+				}
+			}{},
+			want: []string{
+				"breaking: endpoint.port: type changed from types.Int64Type to types.StringType",
+				"requires state upgrade: id: attribute removed",
+			},
+			wantErrors: []string{
+				"Schema version not incremented",
+			},
+		},
+		"VersionDown": {
+			new: struct {
+				Name     types.String `required:"true"`
+				ID       types.String `computed:"true"`
+				Size     types.Int64  `optional:"true"`
+				Zone     types.String `optional:"true"`
+				Endpoint []struct {
+					Port types.Int64 `required:"true"`
+				}
+			}{},
+			want: []string{},
+			wantErrors: []string{
+				"Invalid schema version",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			changes, diags := Diff(New(testDiffModel{}), New(test.new))
+
+			got := []string{}
+			for _, c := range changes {
+				got = append(got, c.String())
+			}
+
+			if diff := deep.Equal(got, test.want); diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, test.want, diff)
+			}
+
+			gotErrors := []string{}
+			for _, d := range diags.Errors() {
+				gotErrors = append(gotErrors, d.Summary())
+			}
+
+			if test.wantErrors == nil {
+				test.wantErrors = []string{}
+			}
+
+			if diff := deep.Equal(gotErrors, test.wantErrors); diff != nil {
+				t.Errorf("got errors: %+v\nwant: %+v\ndifference: %v", gotErrors, test.wantErrors, diff)
+			}
+		})
+	}
+}
+
+func TestDiffModels(t *testing.T) {
+	t.Parallel()
+
+	changes, diags := DiffModels(testDiffModel{}, struct {
+		_        struct{}     `version:"1"`
+		ID       types.String `computed:"true"`
+		Name     types.String `required:"true" pmods:"replace"`
+		Size     types.Int64  `optional:"true"`
+		Zone     types.String `optional:"true"`
+		Endpoint []struct {
+			Port types.Int64 `required:"true"`
+		}
+	}{})
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	want := []string{
+		"breaking: name: replace plan modifier added: If the value of this attribute changes, Terraform will destroy and recreate the resource.",
+	}
+
+	got := []string{}
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, want, diff)
+	}
+
+	_, diags = DiffModels(testDiffModel{}, struct {
+		Name types.String `valid:"betwen(1,2)"`
+	}{})
+
+	if diags.ErrorsCount() != 1 {
+		t.Errorf("got %d errors, want 1: %v", diags.ErrorsCount(), diags)
+	}
+}
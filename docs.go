@@ -0,0 +1,597 @@
+package mdlschm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// docField is an attribute or block as it appears in the documentation.
+type docField struct {
+	name        string
+	required    bool
+	optional    bool
+	computed    bool
+	sensitive   bool
+	description string
+	deprecation string
+	notes       []string
+	nested      []docField
+	hasNested   bool
+}
+
+// Docs renders registry-style "Argument Reference" and "Attribute Reference"
+// Markdown sections for a model, with a section for each nested block or
+// nested attribute. Besides descriptions, the text includes valid values
+// (oneof), ranges (between), defaults and whether changes force a new
+// resource (replace), all from the model's tags.
+func Docs(model any) (string, diag.Diagnostics) {
+	schm, diags := NewE(model)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	return renderDocs(modelDocFields(reflect.TypeOf(model), schm.Attributes, schm.Blocks)), diags
+}
+
+// DocsFromSchema works like Docs but, without a model's tags, gets the extra
+// text from the schema's validator and plan modifier descriptions.
+func DocsFromSchema(schm tfsdk.Schema) string {
+	return renderDocs(schemaDocFields(schm.Attributes, schm.Blocks))
+}
+
+func modelDocFields(t reflect.Type, attrs map[string]tfsdk.Attribute, blocks map[string]tfsdk.Block) []docField {
+	fields := []docField{}
+	siblings := siblingNames(t)
+
+	for _, f := range modelFields(t) {
+		tags := string(f.Tag)
+
+		// problems with names were already reported by NewE
+		name := attrName(f.Name, tags, f.Name, &diag.Diagnostics{})
+
+		if a, ok := attrs[name]; ok {
+			df := attrDocField(name, a)
+			df.notes = tagNotes(name, tags, a.FrameworkType(), siblings)
+
+			if r := kindValidators(f.Type); r != "" {
+				df.notes = append(df.notes, tagNotes(name, fmt.Sprintf(`%s:"%s"`, TagValidators, r), a.FrameworkType(), siblings)...)
+			}
+
+			if a.Attributes != nil {
+				df.hasNested = true
+				df.nested = modelDocFields(structElem(f.Type), nestedAttributes(a), nil)
+			}
+
+			fields = append(fields, df)
+			continue
+		}
+
+		if b, ok := blocks[name]; ok {
+			df := blockDocField(name, b)
+			df.required = tagValue(TagRequired, tags) == TagTrue
+			df.optional = !df.required
+			df.notes = tagNotes(name, tags, nil, siblings)
+
+			// timeouts have no tags of their own to document
+			if isTimeouts(f.Type) {
+				df.nested = schemaDocFields(b.Attributes, nil)
+			} else {
+				df.nested = modelDocFields(structElem(f.Type), b.Attributes, b.Blocks)
+			}
+
+			fields = append(fields, df)
+		}
+	}
+
+	return fields
+}
+
+func schemaDocFields(attrs map[string]tfsdk.Attribute, blocks map[string]tfsdk.Block) []docField {
+	fields := []docField{}
+
+	for _, name := range sortedKeys(attrs) {
+		a := attrs[name]
+
+		df := attrDocField(name, a)
+		df.notes = schemaNotes(a.PlanModifiers, a.Validators)
+
+		if a.Attributes != nil {
+			df.hasNested = true
+			df.nested = schemaDocFields(nestedAttributes(a), nil)
+		}
+
+		fields = append(fields, df)
+	}
+
+	for _, name := range sortedKeys(blocks) {
+		b := blocks[name]
+
+		df := blockDocField(name, b)
+		df.required = b.MinItems > 0
+		df.optional = !df.required
+		df.notes = schemaNotes(b.PlanModifiers, b.Validators)
+		df.nested = schemaDocFields(b.Attributes, b.Blocks)
+
+		fields = append(fields, df)
+	}
+
+	return fields
+}
+
+func attrDocField(name string, a tfsdk.Attribute) docField {
+	df := docField{
+		name:        name,
+		required:    a.Required,
+		optional:    a.Optional,
+		computed:    a.Computed,
+		sensitive:   a.Sensitive,
+		description: a.MarkdownDescription,
+		deprecation: a.DeprecationMessage,
+	}
+
+	if df.description == "" {
+		df.description = a.Description
+	}
+
+	return df
+}
+
+func blockDocField(name string, b tfsdk.Block) docField {
+	df := docField{
+		name:        name,
+		description: b.MarkdownDescription,
+		deprecation: b.DeprecationMessage,
+		hasNested:   true,
+	}
+
+	if df.description == "" {
+		df.description = b.Description
+	}
+
+	return df
+}
+
+// tagNotes returns sentences describing the validators and plan modifiers in
+// the tags of the field called name, of type t or, if nil, a block, with
+// siblings from siblingNames.
+func tagNotes(name, tags string, t attr.Type, siblings map[string]string) []string {
+	notes := []string{}
+
+	attrType := SpecialTypeBlock
+	if t != nil {
+		attrType = baseType(t)
+	}
+
+	// problems with tags were already reported by NewE
+	vals, _ := parseTagValue(tagValue(TagValidators, tags))
+
+	notes = append(notes, valueNotes(vals, attrType, t)...)
+
+	for _, name := range []string{TagValidatorEach, TagValidatorKeys} {
+		c, ok := findCall(vals, name)
+		if !ok {
+			continue
+		}
+
+		inner := []tagCall{}
+		for _, a := range c.args {
+			switch {
+			case a.call != nil:
+				inner = append(inner, *a.call)
+			case a.key == "" && isFormatValidator(a.value):
+				inner = append(inner, tagCall{name: a.value, pos: a.pos})
+			}
+		}
+
+		label, et := "Each item", collectionElemType(t)
+		if name == TagValidatorKeys {
+			label, et = "Each key", types.StringType
+		}
+
+		if et == nil {
+			continue
+		}
+
+		for _, n := range valueNotes(inner, baseType(et), et) {
+			notes = append(notes, fmt.Sprintf("%s: %s", label, n))
+		}
+	}
+
+	for _, c := range vals {
+		names := []string{}
+		for _, v := range c.values() {
+			names = append(names, siblings[v])
+		}
+
+		switch c.name {
+		case TagValidatorConflicts:
+			notes = append(notes, fmt.Sprintf("Conflicts with %s.", codeList(names)))
+		case TagValidatorExactlyOneOf:
+			notes = append(notes, fmt.Sprintf("Exactly one of %s must be set.", codeList(append([]string{name}, names...))))
+		case TagValidatorAtLeastOneOf:
+			notes = append(notes, fmt.Sprintf("At least one of %s must be set.", codeList(append([]string{name}, names...))))
+		case TagValidatorAlsoRequires:
+			notes = append(notes, fmt.Sprintf("Also requires %s.", codeList(names)))
+		}
+	}
+
+	pms, _ := parseTagValue(tagValue(TagPlanModifiers, tags))
+
+	if c, ok := findCall(pms, TagPlanModifierDefault); ok && len(c.args) == 1 {
+		switch a := c.args[0]; a.key {
+		case "":
+			notes = append(notes, fmt.Sprintf("Defaults to `%s`.", a.value))
+		case TagDefaultEnv:
+			notes = append(notes, fmt.Sprintf("Defaults to the value of the `%s` environment variable.", a.value))
+		case TagDefaultAttr:
+			notes = append(notes, fmt.Sprintf("Defaults to the value of `%s`.", siblings[a.value]))
+		}
+	}
+
+	// conditional replacements and registered plan modifiers describe
+	// themselves
+	var discard diag.Diagnostics
+
+	if c, ok := findCall(pms, TagPlanModifierReplace); ok {
+		if len(c.args) == 0 {
+			notes = append(notes, "Changing this forces a new resource.")
+		} else if pm := replacePlanModifier(c, attrType, name, &discard); pm != nil {
+			notes = append(notes, sentence(pm.Description(context.Background())))
+		}
+	}
+
+	for _, pm := range registeredPlanModifiers(pms, t, name, &discard) {
+		notes = append(notes, sentence(pm.Description(context.Background())))
+	}
+
+	return notes
+}
+
+// valueNotes returns sentences describing validators that check values of
+// type t.
+func valueNotes(vals []tagCall, attrType string, t attr.Type) []string {
+	notes := []string{}
+
+	if c, ok := findCall(vals, TagValidatorOneOf); ok {
+		notes = append(notes, fmt.Sprintf("Valid values are %s.", codeList(c.values())))
+	}
+
+	if c, ok := findCall(vals, TagValidatorNoneOf); ok {
+		notes = append(notes, fmt.Sprintf("Cannot be %s.", codeList(c.values())))
+	}
+
+	if c, ok := findCall(vals, TagValidatorBetween); ok && len(c.args) == 2 {
+		args := c.values()
+
+		switch attrType {
+		case "types.String":
+			notes = append(notes, fmt.Sprintf("Must be between %s and %s characters long.", args[0], args[1]))
+		case "types.ListType", "types.MapType", "types.SetType", SpecialTypeBlock:
+			notes = append(notes, fmt.Sprintf("Must have between %s and %s items.", args[0], args[1]))
+		default:
+			notes = append(notes, fmt.Sprintf("Must be between %s and %s.", args[0], args[1]))
+		}
+	}
+
+	if c, ok := findCall(vals, TagValidatorRegex); ok && len(c.args) > 0 {
+		if len(c.args) > 1 {
+			notes = append(notes, sentence(c.args[1].value))
+		} else {
+			notes = append(notes, fmt.Sprintf("Must match the regular expression `%s`.", c.args[0].value))
+		}
+	}
+
+	if c, ok := findCall(vals, TagValidatorLenAtLeast); ok && len(c.args) == 1 {
+		notes = append(notes, fmt.Sprintf("Must be at least %s characters long.", c.args[0].value))
+	}
+
+	if c, ok := findCall(vals, TagValidatorLenAtMost); ok && len(c.args) == 1 {
+		notes = append(notes, fmt.Sprintf("Must be at most %s characters long.", c.args[0].value))
+	}
+
+	if c, ok := findCall(vals, TagValidatorPrefix); ok && len(c.args) == 1 {
+		notes = append(notes, fmt.Sprintf("Must start with `%s`.", c.args[0].value))
+	}
+
+	if c, ok := findCall(vals, TagValidatorSuffix); ok && len(c.args) == 1 {
+		notes = append(notes, fmt.Sprintf("Must end with `%s`.", c.args[0].value))
+	}
+
+	if c, ok := findCall(vals, TagValidatorContains); ok && len(c.args) == 1 {
+		notes = append(notes, fmt.Sprintf("Must contain `%s`.", c.args[0].value))
+	}
+
+	if hasCall(vals, TagValidatorDuration) {
+		notes = append(notes, "Must be a duration, eg, `30s`, `10m` or `2h45m`.")
+	}
+
+	if hasCall(vals, TagValidatorRFC3339) {
+		notes = append(notes, "Must be an RFC3339 timestamp, eg, `2006-01-02T15:04:05Z`.")
+	}
+
+	if hasCall(vals, TagValidatorBase64) {
+		notes = append(notes, "Must be base64 encoded.")
+	}
+
+	// registered validators describe themselves, their problems having been
+	// reported by NewE
+	var discard diag.Diagnostics
+	for _, v := range registeredValidators(vals, t, "", &discard) {
+		notes = append(notes, sentence(v.Description(context.Background())))
+	}
+
+	return notes
+}
+
+// schemaNotes returns sentences describing plan modifiers and validators
+// already in a schema.
+func schemaNotes(pms tfsdk.AttributePlanModifiers, vals []tfsdk.AttributeValidator) []string {
+	ctx := context.Background()
+	notes := []string{}
+
+	for _, v := range vals {
+		notes = append(notes, sentence(v.Description(ctx)))
+	}
+
+	replace := resource.RequiresReplace().Description(ctx)
+
+	for _, pm := range pms {
+		if dv, ok := pm.(*defaultValuePlanModifier); ok {
+			notes = append(notes, fmt.Sprintf("Defaults to `%s`.", valueText(dv.DefaultValue)))
+			continue
+		}
+
+		if pm.Description(ctx) == replace {
+			notes = append(notes, "Changing this forces a new resource.")
+			continue
+		}
+
+		notes = append(notes, sentence(pm.Description(context.Background())))
+	}
+
+	return notes
+}
+
+func renderDocs(fields []docField) string {
+	var sb strings.Builder
+
+	sb.WriteString("## Argument Reference\n\n")
+
+	required := []docField{}
+	optional := []docField{}
+	exported := []docField{}
+
+	for _, f := range fields {
+		switch {
+		case f.required:
+			required = append(required, f)
+		case f.optional:
+			optional = append(optional, f)
+		default:
+			exported = append(exported, f)
+		}
+	}
+
+	if len(required) > 0 {
+		sb.WriteString("The following arguments are required:\n\n")
+		renderFields(&sb, required, true)
+	}
+
+	if len(optional) > 0 {
+		sb.WriteString("The following arguments are optional:\n\n")
+		renderFields(&sb, optional, true)
+	}
+
+	if len(required) == 0 && len(optional) == 0 {
+		sb.WriteString("There are no arguments.\n\n")
+	}
+
+	sb.WriteString("## Attribute Reference\n\n")
+
+	if len(exported) > 0 {
+		sb.WriteString("In addition to all arguments above, the following attributes are exported:\n\n")
+		renderFields(&sb, exported, false)
+	} else {
+		sb.WriteString("No additional attributes are exported.\n\n")
+	}
+
+	renderSections(&sb, "", fields)
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func renderFields(sb *strings.Builder, fields []docField, labels bool) {
+	for _, f := range fields {
+		parts := []string{}
+
+		if labels {
+			parts = append(parts, fmt.Sprintf("(%s)", strings.Join(docLabels(f), ", ")))
+		}
+
+		if f.description != "" {
+			parts = append(parts, sentence(f.description))
+		}
+
+		parts = append(parts, f.notes...)
+
+		if f.hasNested {
+			parts = append(parts, fmt.Sprintf("See `%s` below.", f.name))
+		}
+
+		if f.deprecation != "" {
+			parts = append(parts, fmt.Sprintf("**Deprecated**: %s", sentence(f.deprecation)))
+		}
+
+		if len(parts) > 0 {
+			sb.WriteString(fmt.Sprintf("* `%s` - %s\n", f.name, strings.Join(parts, " ")))
+		} else {
+			sb.WriteString(fmt.Sprintf("* `%s`\n", f.name))
+		}
+	}
+
+	sb.WriteString("\n")
+}
+
+func renderSections(sb *strings.Builder, parentPath string, fields []docField) {
+	for _, f := range fields {
+		if !f.hasNested {
+			continue
+		}
+
+		fp := joinFieldPath(parentPath, f.name)
+
+		sb.WriteString(fmt.Sprintf("### `%s`\n\n", fp))
+
+		if len(f.nested) == 0 {
+			sb.WriteString("This has no arguments or attributes.\n\n")
+			continue
+		}
+
+		renderFields(sb, f.nested, true)
+	}
+
+	for _, f := range fields {
+		if f.hasNested {
+			renderSections(sb, joinFieldPath(parentPath, f.name), f.nested)
+		}
+	}
+}
+
+func docLabels(f docField) []string {
+	labels := []string{}
+
+	switch {
+	case f.required:
+		labels = append(labels, "Required")
+	case f.optional:
+		labels = append(labels, "Optional")
+	default:
+		labels = append(labels, "Computed")
+	}
+
+	if f.sensitive {
+		labels = append(labels, "Sensitive")
+	}
+
+	return labels
+}
+
+// nestedAttributes returns the attributes nested in a nested attribute.
+func nestedAttributes(a tfsdk.Attribute) map[string]tfsdk.Attribute {
+	attrs := make(map[string]tfsdk.Attribute)
+
+	for k, v := range a.Attributes.GetAttributes() {
+		if na, ok := v.(tfsdk.Attribute); ok {
+			attrs[k] = na
+		}
+	}
+
+	return attrs
+}
+
+// structElem returns the struct type of a struct, slice of structs or map of
+// structs.
+func structElem(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		return t.Elem()
+	}
+
+	return t
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func codeList(values []string) string {
+	quoted := []string{}
+	for _, v := range values {
+		quoted = append(quoted, fmt.Sprintf("`%s`", strings.TrimSpace(v)))
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// sentence capitalizes s and ends it with a period.
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s
+	}
+
+	s = strings.ToUpper(s[:1]) + s[1:]
+
+	if !strings.HasSuffix(s, ".") {
+		s += "."
+	}
+
+	return s
+}
+
+// valueText returns a plain representation of a value, without the quotes
+// that attr.Value.String adds to strings.
+func valueText(v attr.Value) string {
+	switch v := v.(type) {
+	case types.String:
+		return v.Value
+	case types.List:
+		return fmt.Sprintf("[%s]", strings.Join(literalItems(v.Elems), ","))
+	case types.Set:
+		return fmt.Sprintf("[%s]", strings.Join(literalItems(v.Elems), ","))
+	case types.Map:
+		return fmt.Sprintf("{%s}", strings.Join(literalPairs(v.Elems), ","))
+	case types.Object:
+		return fmt.Sprintf("{%s}", strings.Join(literalPairs(v.Attrs), ","))
+	}
+
+	return v.String()
+}
+
+// literalItems returns the text of values as items in a list literal.
+func literalItems(vals []attr.Value) []string {
+	items := []string{}
+	for _, v := range vals {
+		items = append(items, literalItem(v))
+	}
+
+	return items
+}
+
+// literalPairs returns the text of values as k=v pairs in a map literal,
+// leaving out nulls.
+func literalPairs(vals map[string]attr.Value) []string {
+	pairs := []string{}
+	for _, k := range sortedKeys(vals) {
+		if vals[k].IsNull() {
+			continue
+		}
+
+		pairs = append(pairs, fmt.Sprintf("%s=%s", quoteLiteral(k), literalItem(vals[k])))
+	}
+
+	return pairs
+}
+
+func literalItem(v attr.Value) string {
+	switch v.(type) {
+	case types.List, types.Set, types.Map, types.Object:
+		return valueText(v)
+	}
+
+	return quoteLiteral(valueText(v))
+}
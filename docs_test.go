@@ -0,0 +1,264 @@
+package mdlschm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDocs(t *testing.T) {
+	t.Parallel()
+
+	model := struct {
+		Name  types.String   `tfsdk:"name" required:"true" pmods:"replace" desc:"Name of the widget" valid:"between(1,64)"`
+		Size  types.Int64    `tfsdk:"size" optional:"true" computed:"true" pmods:"default(3),replace(decrease)" valid:"oneof(1,3,5)"`
+		Token types.String   `tfsdk:"token" optional:"true" sensitive:"true" deprecation:"Use secret instead" valid:"conflicts(Slug)"`
+		Slug  types.String   `tfsdk:"slug" optional:"true" valid:"regex(^[a-z-]+$),lenatmost(32),prefix(w-)"`
+		Zones []types.String `tfsdk:"zones" optional:"true" valid:"each(oneof(a,b))"`
+		Label types.String   `tfsdk:"label" optional:"true" computed:"true" pmods:"default(attr=Name)"`
+		Owner types.String   `tfsdk:"owner" optional:"true" computed:"true" pmods:"default(env=WIDGET_OWNER)"`
+		ID    types.String   `tfsdk:"id" computed:"true" desc:"Widget identifier"`
+		Rules []struct {
+			Port     types.Int64  `tfsdk:"port" required:"true" valid:"between(1,65535)"`
+			Protocol types.String `tfsdk:"protocol" valid:"oneof(tcp,udp)"`
+		} `tfsdk:"rules" required:"true" desc:"Rules for the widget"`
+		Timeouts *Timeouts `tfsdk:"timeouts" timeouts:"create,delete"`
+	}{}
+
+	want := "## Argument Reference\n" +
+		"\n" +
+		"The following arguments are required:\n" +
+		"\n" +
+		"* `name` - (Required) Name of the widget. Must be between 1 and 64 characters long. Changing this forces a new resource.\n" +
+		"* `rules` - (Required) Rules for the widget. See `rules` below.\n" +
+		"\n" +
+		"The following arguments are optional:\n" +
+		"\n" +
+		"* `size` - (Optional) Valid values are `1`, `3`, `5`. Defaults to `3`. If the value of this attribute decreases, Terraform will destroy and recreate the resource.\n" +
+		"* `token` - (Optional, Sensitive) Conflicts with `slug`. **Deprecated**: Use secret instead.\n" +
+		"* `slug` - (Optional) Must match the regular expression `^[a-z-]+$`. Must be at most 32 characters long. Must start with `w-`.\n" +
+		"* `zones` - (Optional) Each item: Valid values are `a`, `b`.\n" +
+		"* `label` - (Optional) Defaults to the value of `name`.\n" +
+		"* `owner` - (Optional) Defaults to the value of the `WIDGET_OWNER` environment variable.\n" +
+		"* `timeouts` - (Optional) See `timeouts` below.\n" +
+		"\n" +
+		"## Attribute Reference\n" +
+		"\n" +
+		"In addition to all arguments above, the following attributes are exported:\n" +
+		"\n" +
+		"* `id` - Widget identifier.\n" +
+		"\n" +
+		"### `rules`\n" +
+		"\n" +
+		"* `port` - (Required) Must be between 1 and 65535.\n" +
+		"* `protocol` - (Optional) Valid values are `tcp`, `udp`.\n" +
+		"\n" +
+		"### `timeouts`\n" +
+		"\n" +
+		"* `create` - (Optional) Value must be a duration, eg, 30s, 10m or 2h45m.\n" +
+		"* `delete` - (Optional) Value must be a duration, eg, 30s, 10m or 2h45m.\n"
+
+	got, diags := Docs(model)
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDocsFromSchema(t *testing.T) {
+	t.Parallel()
+
+	schm := tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:        types.StringType,
+				Required:    true,
+				Description: "Name of the widget",
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.RequiresReplace(),
+				},
+			},
+			"mode": {
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					DefaultValue(types.String{Value: "fast"}),
+				},
+				Validators: []tfsdk.AttributeValidator{
+					stringvalidator.LengthBetween(1, 8),
+				},
+			},
+			"arn": {
+				Type:     types.StringType,
+				Computed: true,
+			},
+			"zones": {
+				Type:     types.MapType{ElemType: types.ListType{ElemType: types.StringType}},
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					DefaultValue(types.Map{
+						ElemType: types.ListType{ElemType: types.StringType},
+						Elems: map[string]attr.Value{
+							"us-west-2": types.List{ElemType: types.StringType, Elems: []attr.Value{types.String{Value: "a"}, types.String{Value: "b, c"}}},
+						},
+					}),
+				},
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"tag": {
+				Attributes: map[string]tfsdk.Attribute{
+					"key": {
+						Type:     types.StringType,
+						Required: true,
+					},
+				},
+				NestingMode: tfsdk.BlockNestingModeSet,
+			},
+		},
+	}
+
+	want := "## Argument Reference\n" +
+		"\n" +
+		"The following arguments are required:\n" +
+		"\n" +
+		"* `name` - (Required) Name of the widget. Changing this forces a new resource.\n" +
+		"\n" +
+		"The following arguments are optional:\n" +
+		"\n" +
+		"* `mode` - (Optional) String length must be between 1 and 8. Defaults to `fast`.\n" +
+		"* `zones` - (Optional) Defaults to `{us-west-2=[a,'b, c']}`.\n" +
+		"* `tag` - (Optional) See `tag` below.\n" +
+		"\n" +
+		"## Attribute Reference\n" +
+		"\n" +
+		"In addition to all arguments above, the following attributes are exported:\n" +
+		"\n" +
+		"* `arn`\n" +
+		"\n" +
+		"### `tag`\n" +
+		"\n" +
+		"* `key` - (Required)\n"
+
+	if got := DocsFromSchema(schm); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDocsEmbedded(t *testing.T) {
+	t.Parallel()
+
+	model := struct {
+		testCommonFields
+		TestRegionalFields
+		Name types.String `required:"true" desc:"Name of the widget"`
+	}{}
+
+	want := "## Argument Reference\n" +
+		"\n" +
+		"The following arguments are required:\n" +
+		"\n" +
+		"* `name` - (Required) Name of the widget.\n" +
+		"\n" +
+		"The following arguments are optional:\n" +
+		"\n" +
+		"* `tags` - (Optional)\n" +
+		"* `region` - (Optional) Conflicts with `name`.\n" +
+		"\n" +
+		"## Attribute Reference\n" +
+		"\n" +
+		"In addition to all arguments above, the following attributes are exported:\n" +
+		"\n" +
+		"* `id`\n" +
+		"* `arn`\n" +
+		"* `tags_all`\n"
+
+	got, diags := Docs(model)
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDocsKinds(t *testing.T) {
+	t.Parallel()
+
+	model := struct {
+		Port   uint16  `required:"true"`
+		Levels []int8  `optional:"true"`
+		Name   *string `optional:"true"`
+	}{}
+
+	want := "## Argument Reference\n" +
+		"\n" +
+		"The following arguments are required:\n" +
+		"\n" +
+		"* `port` - (Required) Must be between 0 and 65535.\n" +
+		"\n" +
+		"The following arguments are optional:\n" +
+		"\n" +
+		"* `levels` - (Optional) Each item: Must be between -128 and 127.\n" +
+		"* `name` - (Optional)\n" +
+		"\n" +
+		"## Attribute Reference\n" +
+		"\n" +
+		"No additional attributes are exported.\n"
+
+	got, diags := Docs(model)
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDocsFormats(t *testing.T) {
+	t.Parallel()
+
+	model := struct {
+		Interval time.Duration   `required:"true"`
+		Expires  time.Time       `optional:"true"`
+		Data     []byte          `optional:"true"`
+		Waits    []time.Duration `optional:"true"`
+	}{}
+
+	want := "## Argument Reference\n" +
+		"\n" +
+		"The following arguments are required:\n" +
+		"\n" +
+		"* `interval` - (Required) Must be a duration, eg, `30s`, `10m` or `2h45m`.\n" +
+		"\n" +
+		"The following arguments are optional:\n" +
+		"\n" +
+		"* `expires` - (Optional) Must be an RFC3339 timestamp, eg, `2006-01-02T15:04:05Z`.\n" +
+		"* `data` - (Optional) Must be base64 encoded.\n" +
+		"* `waits` - (Optional) Each item: Must be a duration, eg, `30s`, `10m` or `2h45m`.\n" +
+		"\n" +
+		"## Attribute Reference\n" +
+		"\n" +
+		"No additional attributes are exported.\n"
+
+	got, diags := Docs(model)
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,757 @@
+package mdlschm
+
+import (
+	"context"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// commonInitialisms are capitalized as a whole in generated Go field names.
+var commonInitialisms = map[string]bool{
+	"acl": true, "api": true, "arn": true, "cidr": true, "cpu": true,
+	"dns": true, "http": true, "https": true, "iam": true, "id": true,
+	"ids": true, "ip": true, "json": true, "kms": true, "sql": true,
+	"ssh": true, "ssl": true, "tcp": true, "tls": true, "ttl": true,
+	"udp": true, "uri": true, "url": true, "uuid": true, "vpc": true,
+}
+
+// GenerateModel is the inverse of New. It returns Go source for a model
+// struct, named typeName, with the tags and nested structs that New needs to
+// reproduce the schema. Validators and plan modifiers that tags cannot
+// express are left out and reported as warnings. Types that New does not
+// produce on its own, such as custom types, are reported as errors.
+func GenerateModel(schm tfsdk.Schema, typeName string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("type %s struct {\n", typeName))
+
+	schemaTags := []string{}
+	if schm.Version != 0 {
+		schemaTags = append(schemaTags, genTag(TagVersion, strconv.FormatInt(schm.Version, 10), "_", &diags))
+	}
+	if schm.Description != "" {
+		schemaTags = append(schemaTags, genTag(TagDescription, schm.Description, "_", &diags))
+	}
+	if schm.MarkdownDescription != "" {
+		schemaTags = append(schemaTags, genTag(TagMarkdownDescription, schm.MarkdownDescription, "_", &diags))
+	}
+	if schm.DeprecationMessage != "" {
+		schemaTags = append(schemaTags, genTag(TagDeprecationMessage, schm.DeprecationMessage, "_", &diags))
+	}
+	if len(schemaTags) > 0 {
+		sb.WriteString(fmt.Sprintf("_ struct{} `%s`\n", strings.Join(schemaTags, " ")))
+	}
+
+	genFields(&sb, schm.Attributes, schm.Blocks, "", &diags)
+
+	sb.WriteString("}\n")
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		diags.AddError("Invalid generated model", err.Error())
+		return sb.String(), diags
+	}
+
+	return string(src), diags
+}
+
+func genFields(sb *strings.Builder, attrs map[string]tfsdk.Attribute, blocks map[string]tfsdk.Block, parentPath string, diags *diag.Diagnostics) {
+	used := make(map[string]bool)
+
+	for _, name := range sortedKeys(attrs) {
+		genAttribute(sb, name, uniqueFieldName(name, used), attrs[name], joinFieldPath(parentPath, name), diags)
+	}
+
+	for _, name := range sortedKeys(blocks) {
+		genBlock(sb, name, uniqueFieldName(name, used), blocks[name], joinFieldPath(parentPath, name), diags)
+	}
+}
+
+func genAttribute(sb *strings.Builder, name, field string, a tfsdk.Attribute, fieldPath string, diags *diag.Diagnostics) {
+	tags := []string{genTag(TagTfsdk, name, fieldPath, diags)}
+
+	if a.Required {
+		tags = append(tags, genTag(TagRequired, TagTrue, fieldPath, diags))
+	}
+	if a.Optional {
+		tags = append(tags, genTag(TagOptional, TagTrue, fieldPath, diags))
+	}
+	if a.Computed {
+		tags = append(tags, genTag(TagComputed, TagTrue, fieldPath, diags))
+	}
+	if a.Sensitive {
+		tags = append(tags, genTag(TagSensitive, TagTrue, fieldPath, diags))
+	}
+
+	var goType, attrType string
+	companions := []string{}
+
+	if a.Attributes != nil {
+		var elem string
+
+		switch a.Attributes.Type().(type) {
+		case types.ListType:
+			elem, attrType = "[]", SpecialTypeListNested
+			tags = append(tags, genTag(TagNesting, TagNestingAttribute, fieldPath, diags))
+		case types.SetType:
+			elem, attrType = "[]", SpecialTypeSetNested
+			tags = append(tags, genTag(TagNesting, TagNestingAttribute, fieldPath, diags), genTag(TagCollection, TagCollectionSet, fieldPath, diags))
+		case types.MapType:
+			elem, attrType = "map[string]", SpecialTypeMapNested
+		default:
+			attrType = SpecialTypeSingleNested
+			tags = append(tags, genTag(TagNesting, TagNestingAttribute, fieldPath, diags))
+		}
+
+		var nsb strings.Builder
+		genFields(&nsb, nestedAttributes(a), nil, fieldPath, diags)
+		goType = fmt.Sprintf("%sstruct {\n%s}", elem, nsb.String())
+	} else {
+		var extra []string
+		goType, extra, companions = genType(a.Type, field, fieldPath, diags)
+		tags = append(tags, extra...)
+		attrType = baseType(a.Type)
+	}
+
+	if v := genValidators(a.Validators, attrType, a.Type, false, strings.Join(tags, " "), fieldPath, diags); v != "" {
+		tags = append(tags, genTag(TagValidators, v, fieldPath, diags))
+	}
+
+	if v := genPlanModifiers(a.PlanModifiers, attrType, a.FrameworkType(), fieldPath, diags); v != "" {
+		tags = append(tags, genTag(TagPlanModifiers, v, fieldPath, diags))
+	}
+
+	tags = append(tags, genDescriptions(a.Description, a.MarkdownDescription, a.DeprecationMessage, fieldPath, diags)...)
+
+	sb.WriteString(fmt.Sprintf("%s %s `%s`\n", field, goType, strings.Join(tags, " ")))
+
+	for _, c := range companions {
+		sb.WriteString(c)
+	}
+}
+
+func genBlock(sb *strings.Builder, name, field string, b tfsdk.Block, fieldPath string, diags *diag.Diagnostics) {
+	ctx := context.Background()
+
+	tags := []string{genTag(TagTfsdk, name, fieldPath, diags)}
+
+	if ops, ok := blockTimeouts(b); ok {
+		if len(ops) < len(timeoutsOperations) {
+			tags = append(tags, genTag(TagTimeouts, strings.Join(ops, ","), fieldPath, diags))
+		}
+
+		if v := genPlanModifiers(b.PlanModifiers, SpecialTypeSingleBlock, b.Type(), fieldPath, diags); v != "" {
+			tags = append(tags, genTag(TagPlanModifiers, v, fieldPath, diags))
+		}
+
+		tags = append(tags, genDescriptions(b.Description, b.MarkdownDescription, b.DeprecationMessage, fieldPath, diags)...)
+
+		sb.WriteString(fmt.Sprintf("%s *mdlschm.Timeouts `%s`\n", field, strings.Join(tags, " ")))
+		return
+	}
+	elem := "[]"
+	fromSlice := true
+	vals := b.Validators
+
+	switch b.NestingMode {
+	case tfsdk.BlockNestingModeSingle:
+		elem, fromSlice = "", false
+		tags = append(tags, genTag(TagCollection, TagCollectionSingle, fieldPath, diags))
+	case tfsdk.BlockNestingModeSet:
+		tags = append(tags, genTag(TagCollection, TagCollectionSet, fieldPath, diags))
+	}
+
+	// min and max items, as in JSON schemas, become what New uses instead
+	if b.NestingMode != tfsdk.BlockNestingModeSingle && len(vals) == 0 && (b.MinItems != 0 || b.MaxItems != 0) {
+		switch {
+		case b.MinItems <= 1 && b.MaxItems == 1:
+			elem, fromSlice = "", false
+		case b.MinItems <= 1 && b.MaxItems == 0:
+		default:
+			addFieldWarning(diags, fieldPath, fmt.Sprintf("block size (%d to %d items) cannot be expressed as a tag", b.MinItems, b.MaxItems))
+		}
+
+		if b.MinItems > 0 {
+			tags = append(tags, genTag(TagRequired, TagTrue, fieldPath, diags))
+		}
+	}
+
+	// New adds size validators to list and set blocks unless between is used
+	if b.NestingMode != tfsdk.BlockNestingModeSingle && len(vals) == 1 {
+		set := b.NestingMode == tfsdk.BlockNestingModeSet
+
+		magic := map[string]string{}
+		if set {
+			magic[setvalidator.SizeBetween(0, 1).Description(ctx)] = "maxone"
+			magic[setvalidator.SizeBetween(1, 1).Description(ctx)] = "maxonerequired"
+			magic[setvalidator.SizeAtLeast(1).Description(ctx)] = "required"
+		} else {
+			magic[listvalidator.SizeBetween(0, 1).Description(ctx)] = "maxone"
+			magic[listvalidator.SizeBetween(1, 1).Description(ctx)] = "maxonerequired"
+			magic[listvalidator.SizeAtLeast(1).Description(ctx)] = "required"
+		}
+
+		switch magic[vals[0].Description(ctx)] {
+		case "maxone":
+			elem, fromSlice, vals = "", false, nil
+		case "maxonerequired":
+			elem, fromSlice, vals = "", false, nil
+			tags = append(tags, genTag(TagRequired, TagTrue, fieldPath, diags))
+		case "required":
+			vals = nil
+			tags = append(tags, genTag(TagRequired, TagTrue, fieldPath, diags))
+		}
+	}
+
+	if len(vals) > 0 {
+		blockType := SpecialTypeBlock
+		if b.NestingMode == tfsdk.BlockNestingModeSingle {
+			blockType = SpecialTypeSingleBlock
+		}
+
+		if v := genValidators(vals, blockType, nil, fromSlice, strings.Join(tags, " "), fieldPath, diags); v != "" {
+			tags = append(tags, genTag(TagValidators, v, fieldPath, diags))
+		}
+	}
+
+	if v := genPlanModifiers(b.PlanModifiers, SpecialTypeBlock, b.Type(), fieldPath, diags); v != "" {
+		tags = append(tags, genTag(TagPlanModifiers, v, fieldPath, diags))
+	}
+
+	tags = append(tags, genDescriptions(b.Description, b.MarkdownDescription, b.DeprecationMessage, fieldPath, diags)...)
+
+	var nsb strings.Builder
+	genFields(&nsb, b.Attributes, b.Blocks, fieldPath, diags)
+
+	sb.WriteString(fmt.Sprintf("%s %sstruct {\n%s} `%s`\n", field, elem, nsb.String(), strings.Join(tags, " ")))
+}
+
+// genType returns the Go type, extra tags and any companion struct fields
+// for an attribute type.
+func genType(t attr.Type, field, fieldPath string, diags *diag.Diagnostics) (string, []string, []string) {
+	if s := primitiveGoType(t); s != "" {
+		return s, nil, nil
+	}
+
+	switch tt := t.(type) {
+	case types.ListType:
+		if s := primitiveGoType(tt.ElemType); s != "" {
+			return "[]" + s, nil, nil
+		}
+	case types.SetType:
+		if s := primitiveGoType(tt.ElemType); s != "" {
+			return "[]" + s, []string{genTag(TagCollection, TagCollectionSet, fieldPath, diags)}, nil
+		}
+	case types.MapType:
+		if s := primitiveGoType(tt.ElemType); s != "" {
+			return "map[string]" + s, nil, nil
+		}
+	}
+
+	goType := ""
+	switch t.(type) {
+	case types.ListType:
+		goType = "types.List"
+	case types.MapType:
+		goType = "types.Map"
+	case types.ObjectType:
+		goType = "types.Object"
+	case types.SetType:
+		goType = "types.Set"
+	default:
+		addFieldError(diags, fieldPath, fmt.Sprintf("unsupported type %s, register it with RegisterType and change the field type", t))
+		return "types.String", nil, nil
+	}
+
+	companions := []string{}
+	spec := genElemSpec(t, lowerFirst(field), &companions, fieldPath, diags)
+	if _, ok := t.(types.ObjectType); !ok {
+		// collections hold their element spec, objects are the spec
+		spec = strings.TrimSuffix(strings.TrimPrefix(spec, specPrefix(t)), ")")
+	}
+
+	return goType, []string{genTag(TagElem, spec, fieldPath, diags)}, companions
+}
+
+// genElemSpec returns the elem tag value for a type, adding companion struct
+// fields for objects.
+func genElemSpec(t attr.Type, companion string, companions *[]string, fieldPath string, diags *diag.Diagnostics) string {
+	switch tt := t.(type) {
+	case types.ListType:
+		return fmt.Sprintf("%s(%s)", TagElemList, genElemSpec(tt.ElemType, companion, companions, fieldPath, diags))
+	case types.MapType:
+		return fmt.Sprintf("%s(%s)", TagElemMap, genElemSpec(tt.ElemType, companion, companions, fieldPath, diags))
+	case types.SetType:
+		return fmt.Sprintf("%s(%s)", TagElemSet, genElemSpec(tt.ElemType, companion, companions, fieldPath, diags))
+	case types.ObjectType:
+		name := companion + "Object"
+
+		var csb strings.Builder
+		used := make(map[string]bool)
+		for _, k := range sortedKeys(tt.AttrTypes) {
+			field := uniqueFieldName(k, used)
+			goType, extra, nested := genType(tt.AttrTypes[k], field, joinFieldPath(fieldPath, k), diags)
+			tags := append([]string{genTag(TagTfsdk, k, fieldPath, diags)}, extra...)
+			csb.WriteString(fmt.Sprintf("%s %s `%s`\n", field, goType, strings.Join(tags, " ")))
+			for _, n := range nested {
+				csb.WriteString(n)
+			}
+		}
+
+		*companions = append(*companions, fmt.Sprintf("%s struct {\n%s}\n", name, csb.String()))
+
+		return fmt.Sprintf("%s(%s)", TagElemObject, name)
+	}
+
+	switch t {
+	case types.BoolType:
+		return TagElemBool
+	case types.Float64Type:
+		return TagElemFloat64
+	case types.Int64Type:
+		return TagElemInt64
+	case types.NumberType:
+		return TagElemNumber
+	case types.StringType:
+		return TagElemString
+	}
+
+	addFieldError(diags, fieldPath, fmt.Sprintf("unsupported element type %s", t))
+	return TagElemString
+}
+
+func specPrefix(t attr.Type) string {
+	switch t.(type) {
+	case types.ListType:
+		return TagElemList + "("
+	case types.MapType:
+		return TagElemMap + "("
+	case types.SetType:
+		return TagElemSet + "("
+	}
+
+	return ""
+}
+
+func primitiveGoType(t attr.Type) string {
+	switch t {
+	case types.BoolType:
+		return "types.Bool"
+	case types.Float64Type:
+		return "types.Float64"
+	case types.Int64Type:
+		return "types.Int64"
+	case types.NumberType:
+		return "types.Number"
+	case types.StringType:
+		return "types.String"
+	}
+
+	return ""
+}
+
+// genValidators returns the valid tag value for validators, checking each
+// candidate by building it again from the tag. Validators that cannot be
+// expressed are reported as warnings.
+func genValidators(vals []tfsdk.AttributeValidator, attrType string, t attr.Type, fromSlice bool, tags, fieldPath string, diags *diag.Diagnostics) string {
+	ctx := context.Background()
+	args := []string{}
+
+	for _, v := range vals {
+		c, ok := matchValidator(v.Description(ctx), reflect.TypeOf(v), attrType, t, fromSlice, tags, fieldPath)
+		if !ok {
+			addFieldWarning(diags, fieldPath, fmt.Sprintf("validator cannot be expressed as a tag: %s", v.Description(ctx)))
+			continue
+		}
+
+		args = append(args, c.String())
+	}
+
+	return strings.Join(args, ",")
+}
+
+// matchValidator returns the valid tag call that builds a validator with the
+// description desc and, unless nil, the type vt.
+func matchValidator(desc string, vt reflect.Type, attrType string, t attr.Type, fromSlice bool, tags, fieldPath string) (tagCall, bool) {
+	ctx := context.Background()
+
+	candidates := validatorCandidates(desc)
+
+	// element validators join the descriptions of the validators they wrap
+	for prefix, name := range map[string]string{
+		"value must satisfy all validations: ": TagValidatorEach,
+		"key must satisfy all validations: ":   TagValidatorKeys,
+	} {
+		et := collectionElemType(t)
+		if name == TagValidatorKeys {
+			et = types.StringType
+		}
+
+		if !strings.HasPrefix(desc, prefix) || et == nil {
+			continue
+		}
+
+		c := tagCall{name: name, hasArgs: true}
+		for _, part := range strings.Split(strings.TrimPrefix(desc, prefix), " + ") {
+			inner, ok := matchValidator(part, nil, baseType(et), et, false, tags, fieldPath)
+			if !ok {
+				break
+			}
+			c.args = append(c.args, tagArg{value: inner.String(), call: &inner})
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	for _, c := range candidates {
+		var rebuilt []tfsdk.AttributeValidator
+		var discard diag.Diagnostics
+
+		switch c.name {
+		case TagValidatorConflicts, TagValidatorExactlyOneOf, TagValidatorAtLeastOneOf, TagValidatorAlsoRequires:
+			rebuilt = []tfsdk.AttributeValidator{crossValidator(c.name, c.values())}
+		default:
+			rebuilt = validators([]tagCall{c}, attrType, t, fromSlice, tags, fieldPath, &discard)
+		}
+
+		if len(rebuilt) == 1 && (vt == nil || reflect.TypeOf(rebuilt[0]) == vt) && rebuilt[0].Description(ctx) == desc {
+			return c, true
+		}
+	}
+
+	return tagCall{}, false
+}
+
+// validatorCandidates returns possible valid tag values for a validator
+// based on its description.
+func validatorCandidates(desc string) []tagCall {
+	candidates := []tagCall{}
+
+	var lo, hi float64
+	for _, f := range []string{
+		"list must contain at least %g elements and at most %g elements",
+		"map must contain at least %g elements and at most %g elements",
+		"set must contain at least %g elements and at most %g elements",
+		"string length must be between %g and %g",
+		"value must be between %g and %g",
+	} {
+		if n, _ := fmt.Sscanf(desc, f, &lo, &hi); n == 2 {
+			candidates = append(candidates, newTagCall(TagValidatorBetween, formatNumber(lo), formatNumber(hi)))
+		}
+	}
+
+	// ints too large for floats
+	var ilo, ihi int64
+	if n, _ := fmt.Sscanf(desc, "value must be between %d and %d", &ilo, &ihi); n == 2 {
+		candidates = append(candidates, newTagCall(TagValidatorBetween, strconv.FormatInt(ilo, 10), strconv.FormatInt(ihi, 10)))
+	}
+
+	for _, name := range []string{TagValidatorDuration, TagValidatorRFC3339, TagValidatorBase64} {
+		if desc == (formatValidator{Format: name}).Description(context.Background()) {
+			candidates = append(candidates, tagCall{name: name})
+		}
+	}
+
+	var n int
+	for f, tag := range map[string]string{
+		"string length must be at least %d": TagValidatorLenAtLeast,
+		"string length must be at most %d":  TagValidatorLenAtMost,
+	} {
+		if c, _ := fmt.Sscanf(desc, f, &n); c == 1 {
+			candidates = append(candidates, newTagCall(tag, strconv.Itoa(n)))
+		}
+	}
+
+	for prefix, tag := range map[string]string{
+		"value must start with ": TagValidatorPrefix,
+		"value must end with ":   TagValidatorSuffix,
+		"value must contain ":    TagValidatorContains,
+	} {
+		if v, err := strconv.Unquote(strings.TrimPrefix(desc, prefix)); strings.HasPrefix(desc, prefix) && err == nil {
+			candidates = append(candidates, newTagCall(tag, v))
+		}
+	}
+
+	for prefix, tag := range map[string]string{
+		"Ensure that if an attribute is set, these are not set: ":              TagValidatorConflicts,
+		"Ensure that one and only one attribute from this collection is set: ": TagValidatorExactlyOneOf,
+		"Ensure that at least one attribute from this collection is set: ":     TagValidatorAtLeastOneOf,
+		"Ensure that if an attribute is set, also these are set: ":             TagValidatorAlsoRequires,
+	} {
+		if !strings.HasPrefix(desc, prefix) {
+			continue
+		}
+
+		if names, ok := siblingExpressions(strings.TrimPrefix(desc, prefix)); ok {
+			candidates = append(candidates, newTagCall(tag, names...))
+		}
+	}
+
+	if strings.HasPrefix(desc, "value must match regular expression '") && strings.HasSuffix(desc, "'") {
+		pattern := strings.TrimSuffix(strings.TrimPrefix(desc, "value must match regular expression '"), "'")
+		candidates = append(candidates, newTagCall(TagValidatorRegex, pattern))
+	}
+
+	for prefix, tag := range map[string]string{
+		"String must match one of: ":  TagValidatorOneOf,
+		"Value must be one of: ":      TagValidatorOneOf,
+		"String must match none of: ": TagValidatorNoneOf,
+		"Value must be none of: ":     TagValidatorNoneOf,
+	} {
+		if !strings.HasPrefix(desc, prefix) {
+			continue
+		}
+
+		values, ok := unquoteList(strings.TrimPrefix(desc, prefix))
+		if !ok {
+			continue
+		}
+
+		candidates = append(candidates, newTagCall(tag, values...))
+
+		// string validators quote values that are already quoted
+		if inner, ok := unquoteList(strings.Join(values, " ")); ok && len(inner) == len(values) {
+			candidates = append(candidates, newTagCall(tag, inner...))
+		}
+	}
+
+	return candidates
+}
+
+// siblingExpressions returns the names in a list of sibling path
+// expressions, such as "[<.a,<.b]".
+func siblingExpressions(s string) ([]string, bool) {
+	if u, err := strconv.Unquote(s); err == nil {
+		s = u
+	}
+
+	names := []string{}
+	for _, e := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"), ",") {
+		n := strings.TrimPrefix(e, "<.")
+		if n == e || n == "" || strings.ContainsAny(n, ".[]<") {
+			return nil, false
+		}
+		names = append(names, n)
+	}
+
+	return names, true
+}
+
+// unquoteList parses a list of quoted strings formatted with %q, such as
+// ["a" "b"].
+func unquoteList(s string) ([]string, bool) {
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+
+	values := []string{}
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		q, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return nil, false
+		}
+
+		v, err := strconv.Unquote(q)
+		if err != nil {
+			return nil, false
+		}
+
+		values = append(values, v)
+		s = s[len(q):]
+	}
+
+	return values, true
+}
+
+// genPlanModifiers returns the pmods tag value for plan modifiers. Plan
+// modifiers that cannot be expressed are reported as warnings.
+func genPlanModifiers(pms tfsdk.AttributePlanModifiers, attrType string, t attr.Type, fieldPath string, diags *diag.Diagnostics) string {
+	ctx := context.Background()
+	args := []string{}
+
+	for _, pm := range pms {
+		if dv, ok := pm.(*defaultValuePlanModifier); ok {
+			c := newTagCall(TagPlanModifierDefault, valueText(dv.DefaultValue))
+
+			// the default must survive being parsed back out of the tag
+			var discard diag.Diagnostics
+			rebuilt := pMods([]tagCall{c}, attrType, t, fieldPath, &discard)
+			if len(rebuilt) != 1 || !rebuilt[0].(*defaultValuePlanModifier).DefaultValue.Equal(dv.DefaultValue) {
+				addFieldWarning(diags, fieldPath, fmt.Sprintf("default cannot be expressed as a tag: %s", dv.DefaultValue))
+				continue
+			}
+
+			args = append(args, c.String())
+			continue
+		}
+
+		if df, ok := pm.(*defaultFuncPlanModifier); ok {
+			if c, ok := matchDefaultFunc(df); ok {
+				args = append(args, c.String())
+				continue
+			}
+		}
+
+		switch pm.Description(ctx) {
+		case resource.RequiresReplace().Description(ctx):
+			args = append(args, TagPlanModifierReplace)
+		case resource.UseStateForUnknown().Description(ctx):
+			args = append(args, TagPlanModifierUSFU)
+		default:
+			if c, ok := matchReplace(pm, attrType, fieldPath); ok {
+				args = append(args, c.String())
+				continue
+			}
+
+			addFieldWarning(diags, fieldPath, fmt.Sprintf("plan modifier cannot be expressed as a tag: %s", pm.Description(ctx)))
+		}
+	}
+
+	return strings.Join(args, ",")
+}
+
+// matchDefaultFunc returns the default(env=NAME) or default(attr=name) call
+// that builds a plan modifier like pm.
+func matchDefaultFunc(pm *defaultFuncPlanModifier) (tagCall, bool) {
+	var name string
+
+	if _, err := fmt.Sscanf(pm.Desc, "Sets the default value from the %s environment variable if the attribute is not set", &name); err == nil {
+		if defaultFromEnv(name).Desc == pm.Desc {
+			return tagCall{name: TagPlanModifierDefault, hasArgs: true, args: []tagArg{{key: TagDefaultEnv, value: name}}}, true
+		}
+	}
+
+	if _, err := fmt.Sscanf(pm.Desc, "Sets the default value from the %s attribute if the attribute is not set", &name); err == nil {
+		name = strings.TrimPrefix(name, "<.")
+		if defaultFromAttribute(path.MatchRelative().AtParent().AtName(name)).Desc == pm.Desc {
+			return tagCall{name: TagPlanModifierDefault, hasArgs: true, args: []tagArg{{key: TagDefaultAttr, value: name}}}, true
+		}
+	}
+
+	return tagCall{}, false
+}
+
+// matchReplace returns the conditional replace call that builds a plan
+// modifier like pm.
+func matchReplace(pm tfsdk.AttributePlanModifier, attrType, fieldPath string) (tagCall, bool) {
+	ctx := context.Background()
+
+	candidates := []tagCall{
+		newTagCall(TagPlanModifierReplace, TagReplaceIfSet),
+		newTagCall(TagPlanModifierReplace, TagReplaceIfConfigured),
+		newTagCall(TagPlanModifierReplace, TagReplaceDecrease),
+	}
+
+	for _, name := range registeredReplaceIfNames() {
+		c := tagCall{name: TagPlanModifierReplace, hasArgs: true, args: []tagArg{{key: TagReplaceFunc, value: name}}}
+		candidates = append(candidates, c)
+	}
+
+	for _, c := range candidates {
+		var discard diag.Diagnostics
+
+		r := replacePlanModifier(c, attrType, fieldPath, &discard)
+		if r != nil && reflect.TypeOf(r) == reflect.TypeOf(pm) && r.Description(ctx) == pm.Description(ctx) {
+			return c, true
+		}
+	}
+
+	return tagCall{}, false
+}
+
+func genDescriptions(desc, md, deprecation, fieldPath string, diags *diag.Diagnostics) []string {
+	tags := []string{}
+
+	if desc != "" {
+		tags = append(tags, genTag(TagDescription, desc, fieldPath, diags))
+	}
+	if md != "" {
+		tags = append(tags, genTag(TagMarkdownDescription, md, fieldPath, diags))
+	}
+	if deprecation != "" {
+		tags = append(tags, genTag(TagDeprecationMessage, deprecation, fieldPath, diags))
+	}
+
+	return tags
+}
+
+// genTag returns key:"value", replacing backquotes, which cannot appear in a
+// raw string struct tag.
+func genTag(key, value, fieldPath string, diags *diag.Diagnostics) string {
+	if strings.Contains(value, "`") {
+		addFieldWarning(diags, fieldPath, fmt.Sprintf("%s tag value changed to fit in a struct tag", key))
+		value = strings.ReplaceAll(value, "`", "'")
+	}
+
+	return fmt.Sprintf(`%s:%s`, key, strconv.Quote(value))
+}
+
+// goFieldName converts a snake case attribute name into an exported Go field
+// name, eg, vpc_endpoint_ids becomes VPCEndpointIDs.
+func goFieldName(snake string) string {
+	var sb strings.Builder
+
+	for _, part := range strings.Split(snake, "_") {
+		if part == "" {
+			continue
+		}
+
+		if commonInitialisms[part] {
+			if part == "ids" {
+				sb.WriteString("IDs")
+			} else {
+				sb.WriteString(strings.ToUpper(part))
+			}
+			continue
+		}
+
+		sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	if sb.Len() == 0 || !(sb.String()[0] >= 'A' && sb.String()[0] <= 'Z') {
+		return "Field" + sb.String()
+	}
+
+	return sb.String()
+}
+
+func uniqueFieldName(snake string, used map[string]bool) string {
+	name := goFieldName(snake)
+
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s%d", goFieldName(snake), i)
+	}
+	used[name] = true
+
+	return name
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// addFieldWarning records something about the field at fieldPath that may
+// need attention.
+func addFieldWarning(diags *diag.Diagnostics, fieldPath, detail string) {
+	diags.AddWarning(fmt.Sprintf("Field %s", fieldPath), detail)
+}
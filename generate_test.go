@@ -0,0 +1,227 @@
+package mdlschm
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type testWidgetModel struct {
+	_           struct{}      `version:"2" desc:"A widget"`
+	ID          types.String  `computed:"true" pmods:"usfu"`
+	Name        types.String  `required:"true" valid:"between(1,64)" pmods:"replace"`
+	Kind        types.String  `optional:"true" computed:"true" valid:"oneof(small,large)" pmods:"default(small)"`
+	Count       types.Int64   `optional:"true" valid:"noneof(3,4),conflicts(Ports)"`
+	Ports       []types.Int64 `optional:"true" collection:"set" valid:"between(1,3),each(between(1,1024))" pmods:"replace(decrease)"`
+	Matrix      types.List    `optional:"true" elem:"list(string)" pmods:"default([[a],[b,c]])"`
+	Owner       types.Object  `computed:"true" elem:"object(ownerObject)"`
+	ownerObject struct {
+		Name types.String `tfsdk:"name"`
+	}
+	Routes map[string]struct {
+		Weight types.Int64 `required:"true"`
+	} `optional:"true"`
+	Endpoint struct {
+		URL types.String `required:"true" sensitive:"true"`
+	} `nesting:"attribute" optional:"true"`
+	Rule []struct {
+		Port types.Int64 `required:"true"`
+	} `required:"true" desc:"A rule"`
+	Filter struct {
+		Key types.String `optional:"true"`
+	} `collection:"single"`
+}
+
+const testWidgetModelSource = "type testGeneratedModel struct {\n" +
+	"\t_        struct{}    `version:\"2\" desc:\"A widget\"`\n" +
+	"\tCount    types.Int64 `tfsdk:\"count\" optional:\"true\" valid:\"noneof(3,4),conflicts(ports)\"`\n" +
+	"\tEndpoint struct {\n" +
+	"\t\tURL types.String `tfsdk:\"url\" required:\"true\" sensitive:\"true\"`\n" +
+	"\t} `tfsdk:\"endpoint\" optional:\"true\" nesting:\"attribute\"`\n" +
+	"\tID          types.String `tfsdk:\"id\" computed:\"true\" pmods:\"usfu\"`\n" +
+	"\tKind        types.String `tfsdk:\"kind\" optional:\"true\" computed:\"true\" valid:\"oneof(small,large)\" pmods:\"default(small)\"`\n" +
+	"\tMatrix      types.List   `tfsdk:\"matrix\" optional:\"true\" elem:\"list(string)\" pmods:\"default('[[a],[b,c]]')\"`\n" +
+	"\tName        types.String `tfsdk:\"name\" required:\"true\" valid:\"between(1,64)\" pmods:\"replace\"`\n" +
+	"\tOwner       types.Object `tfsdk:\"owner\" computed:\"true\" elem:\"object(ownerObject)\"`\n" +
+	"\townerObject struct {\n" +
+	"\t\tName types.String `tfsdk:\"name\"`\n" +
+	"\t}\n" +
+	"\tPorts  []types.Int64 `tfsdk:\"ports\" optional:\"true\" collection:\"set\" valid:\"between(1,3),each(between(1,1024))\" pmods:\"replace(decrease)\"`\n" +
+	"\tRoutes map[string]struct {\n" +
+	"\t\tWeight types.Int64 `tfsdk:\"weight\" required:\"true\"`\n" +
+	"\t} `tfsdk:\"routes\" optional:\"true\"`\n" +
+	"\tFilter struct {\n" +
+	"\t\tKey types.String `tfsdk:\"key\" optional:\"true\"`\n" +
+	"\t} `tfsdk:\"filter\" collection:\"single\"`\n" +
+	"\tRule []struct {\n" +
+	"\t\tPort types.Int64 `tfsdk:\"port\" required:\"true\"`\n" +
+	"\t} `tfsdk:\"rule\" required:\"true\" desc:\"A rule\"`\n" +
+	"}\n"
+
+// testGeneratedModel is testWidgetModelSource, the model generated from
+// testWidgetModel's schema.
+type testGeneratedModel struct {
+	_        struct{}    `version:"2" desc:"A widget"`
+	Count    types.Int64 `tfsdk:"count" optional:"true" valid:"noneof(3,4),conflicts(ports)"`
+	Endpoint struct {
+		URL types.String `tfsdk:"url" required:"true" sensitive:"true"`
+	} `tfsdk:"endpoint" optional:"true" nesting:"attribute"`
+	ID          types.String `tfsdk:"id" computed:"true" pmods:"usfu"`
+	Kind        types.String `tfsdk:"kind" optional:"true" computed:"true" valid:"oneof(small,large)" pmods:"default(small)"`
+	Matrix      types.List   `tfsdk:"matrix" optional:"true" elem:"list(string)" pmods:"default('[[a],[b,c]]')"`
+	Name        types.String `tfsdk:"name" required:"true" valid:"between(1,64)" pmods:"replace"`
+	Owner       types.Object `tfsdk:"owner" computed:"true" elem:"object(ownerObject)"`
+	ownerObject struct {
+		Name types.String `tfsdk:"name"`
+	}
+	Ports  []types.Int64 `tfsdk:"ports" optional:"true" collection:"set" valid:"between(1,3),each(between(1,1024))" pmods:"replace(decrease)"`
+	Routes map[string]struct {
+		Weight types.Int64 `tfsdk:"weight" required:"true"`
+	} `tfsdk:"routes" optional:"true"`
+	Filter struct {
+		Key types.String `tfsdk:"key" optional:"true"`
+	} `tfsdk:"filter" collection:"single"`
+	Rule []struct {
+		Port types.Int64 `tfsdk:"port" required:"true"`
+	} `tfsdk:"rule" required:"true" desc:"A rule"`
+}
+
+func TestGenerateModel(t *testing.T) {
+	schm := New(testWidgetModel{})
+
+	got, diags := GenerateModel(schm, "testGeneratedModel")
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if got != testWidgetModelSource {
+		t.Errorf("got:\n%s\nwant:\n%s", got, testWidgetModelSource)
+	}
+
+	// the generated model round trips through New
+	if diff := deep.Equal(New(testGeneratedModel{}), schm); diff != nil {
+		t.Errorf("round trip difference: %v", diff)
+	}
+}
+
+func TestGenerateModelWarnings(t *testing.T) {
+	schm := tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"vpc_endpoint_ids": {
+				Type:     types.ListType{ElemType: types.StringType},
+				Optional: true,
+				Validators: []tfsdk.AttributeValidator{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^vpce-`), "must be an endpoint ID"),
+				},
+			},
+			"note": {
+				Type:        types.StringType,
+				Optional:    true,
+				Description: "Say \"hi\"",
+				Validators: []tfsdk.AttributeValidator{
+					stringvalidator.OneOf("hi, there", "bye"),
+				},
+			},
+		},
+	}
+
+	want := "type model struct {\n" +
+		"\tNote           types.String   `tfsdk:\"note\" optional:\"true\" valid:\"oneof('hi, there',bye)\" desc:\"Say \\\"hi\\\"\"`\n" +
+		"\tVPCEndpointIDs []types.String `tfsdk:\"vpc_endpoint_ids\" optional:\"true\"`\n" +
+		"}\n"
+
+	got, diags := GenerateModel(schm, "model")
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if diags.WarningsCount() != 1 {
+		t.Errorf("expected 1 warning, got %d: %v", diags.WarningsCount(), diags)
+	}
+
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateModelDefaultFrom(t *testing.T) {
+	t.Parallel()
+
+	schm := New(struct {
+		Name   types.String `required:"true"`
+		Alias  types.String `optional:"true" computed:"true" pmods:"default(attr=Name)"`
+		Region types.String `optional:"true" computed:"true" pmods:"default(env=AWS_REGION)"`
+	}{})
+
+	want := "type model struct {\n" +
+		"\tAlias  types.String `tfsdk:\"alias\" optional:\"true\" computed:\"true\" pmods:\"default(attr=name)\"`\n" +
+		"\tName   types.String `tfsdk:\"name\" required:\"true\"`\n" +
+		"\tRegion types.String `tfsdk:\"region\" optional:\"true\" computed:\"true\" pmods:\"default(env=AWS_REGION)\"`\n" +
+		"}\n"
+
+	got, diags := GenerateModel(schm, "model")
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateModelTimeouts(t *testing.T) {
+	t.Parallel()
+
+	schm := New(struct {
+		Name     types.String `required:"true"`
+		Timeouts *Timeouts    `timeouts:"create,delete"`
+		Waits    *Timeouts    `desc:"How long to wait"`
+	}{})
+
+	want := "type model struct {\n" +
+		"\tName     types.String      `tfsdk:\"name\" required:\"true\"`\n" +
+		"\tTimeouts *mdlschm.Timeouts `tfsdk:\"timeouts\" timeouts:\"create,delete\"`\n" +
+		"\tWaits    *mdlschm.Timeouts `tfsdk:\"waits\" desc:\"How long to wait\"`\n" +
+		"}\n"
+
+	got, diags := GenerateModel(schm, "model")
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenerateModelFormats(t *testing.T) {
+	t.Parallel()
+
+	schm := New(struct {
+		Interval time.Duration   `required:"true"`
+		Expires  *time.Time      `optional:"true"`
+		Data     []byte          `optional:"true"`
+		Waits    []time.Duration `optional:"true"`
+	}{})
+
+	want := "type model struct {\n" +
+		"\tData     types.String   `tfsdk:\"data\" optional:\"true\" valid:\"base64\"`\n" +
+		"\tExpires  types.String   `tfsdk:\"expires\" optional:\"true\" valid:\"rfc3339\"`\n" +
+		"\tInterval types.String   `tfsdk:\"interval\" required:\"true\" valid:\"duration\"`\n" +
+		"\tWaits    []types.String `tfsdk:\"waits\" optional:\"true\" valid:\"each(duration)\"`\n" +
+		"}\n"
+
+	got, diags := GenerateModel(schm, "model")
+	if diags.HasError() || diags.WarningsCount() > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -1,22 +1,32 @@
 package mdlschm
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/numbervalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/schemavalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 const (
@@ -30,24 +40,74 @@ const (
 	TagMarkdownDescription = "md"
 	TagPlanModifiers       = "pmods"
 	TagSnakeName           = "snake"
+	TagTfsdk               = "tfsdk"
 	TagValidators          = "valid"
 	TagVersion             = "version"
 	TagCollection          = "collection"
+	TagNesting             = "nesting"
+	TagElem                = "elem"
 
 	// Tag Values
-	TagCollectionList = "list"
-	TagCollectionSet  = "set"
-	TagTrue           = "true"
+	TagCollectionList   = "list"
+	TagCollectionSet    = "set"
+	TagCollectionSingle = "single"
+	TagTrue             = "true"
+	TagTfsdkSkip        = "-"
+
+	TagNestingAttribute = "attribute"
+	TagNestingBlock     = "block"
+
+	TagElemBool    = "bool"
+	TagElemFloat64 = "float64"
+	TagElemInt64   = "int64"
+	TagElemNumber  = "number"
+	TagElemString  = "string"
+	TagElemList    = "list"
+	TagElemMap     = "map"
+	TagElemObject  = "object"
+	TagElemSet     = "set"
 
 	TagPlanModifierReplace = "replace"
 	TagPlanModifierDefault = "default"
 	TagPlanModifierUSFU    = "usfu"
 
+	TagReplaceIfSet        = "ifset"
+	TagReplaceIfConfigured = "ifconfigured"
+	TagReplaceDecrease     = "decrease"
+	TagReplaceFunc         = "func"
+
+	TagDefaultEnv  = "env"
+	TagDefaultAttr = "attr"
+
 	TagValidatorBetween = "between"
 	TagValidatorOneOf   = "oneof"
 	TagValidatorNoneOf  = "noneof"
 
-	SpecialTypeBlock = "block"
+	TagValidatorRegex      = "regex"
+	TagValidatorLenAtLeast = "lenatleast"
+	TagValidatorLenAtMost  = "lenatmost"
+	TagValidatorPrefix     = "prefix"
+	TagValidatorSuffix     = "suffix"
+	TagValidatorContains   = "contains"
+
+	TagValidatorDuration = "duration"
+	TagValidatorRFC3339  = "rfc3339"
+	TagValidatorBase64   = "base64"
+
+	TagValidatorConflicts    = "conflicts"
+	TagValidatorExactlyOneOf = "exactlyoneof"
+	TagValidatorAtLeastOneOf = "atleastoneof"
+	TagValidatorAlsoRequires = "alsorequires"
+
+	TagValidatorEach = "each"
+	TagValidatorKeys = "keys"
+
+	SpecialTypeBlock        = "block"
+	SpecialTypeSingleBlock  = "singleblock"
+	SpecialTypeListNested   = "listnested"
+	SpecialTypeMapNested    = "mapnested"
+	SpecialTypeSetNested    = "setnested"
+	SpecialTypeSingleNested = "singlenested"
 )
 
 type nest struct {
@@ -60,32 +120,53 @@ type nest struct {
 
 // New converts a model struct into a tfsdk.Schema using field types and tags
 // as cues to the schema details. New supports arbitrary depth of nested
-// structs. New also supports many but not all validators and plan modifiers.
+// structs, as blocks or, with the nesting tag, as nested attributes.
+// Embedded structs are flattened into their parent, so models can share
+// fragments, eg, id and arn. New also supports many but not all validators
+// and plan modifiers.
+// New panics if the model has any problems. Use NewE to get them as
+// diagnostics instead.
 func New(model any) tfsdk.Schema {
+	schm, diags := NewE(model)
+
+	if diags.HasError() {
+		msgs := []string{}
+		for _, d := range diags.Errors() {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", d.Summary(), d.Detail()))
+		}
+		panic(strings.Join(msgs, "; "))
+	}
+
+	return schm
+}
+
+// NewE works like New but, rather than panicking on the first problem,
+// returns diagnostics for every problem found in the model. Each diagnostic
+// names the struct field path (e.g., Endpoint.Field) and the offending tag.
+func NewE(model any) (tfsdk.Schema, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
 	if reflect.ValueOf(model).Kind() != reflect.Struct {
-		panic(fmt.Sprintf("internal error (expected struct, got %s)", reflect.ValueOf(model).Kind()))
+		diags.AddError("Invalid model", fmt.Sprintf("expected struct, got %s", reflect.ValueOf(model).Kind()))
+		return tfsdk.Schema{}, diags
 	}
 
-	n := rAttribute(model, "", false, 0)
+	defaults := schemaTags(model)
+
+	checkStructTag(defaults, "_", &diags)
+
+	n := rAttribute(model, "", defaults, false, 0, "", &diags)
 
 	if n.schema == nil {
-		panic("no schema achieved")
+		diags.AddError("Invalid model", "no schema achieved")
+		return tfsdk.Schema{}, diags
 	}
 
-	e := reflect.ValueOf(model)
-
-	for i := 0; i < e.NumField(); i++ {
-		if !e.Type().Field(i).IsExported() && e.Type().Field(i).Name == "_" && e.Type().Field(i).Type.Kind() == reflect.Struct {
-			// special field to define schema-level things, eg, markdown description
-			schemaLevelOptions(n.schema, string(e.Type().Field(i).Tag))
-			break
-		}
+	if defaults != "" {
+		schemaLevelOptions(n.schema, defaults, &diags)
 	}
 
-	//return tfsdk.Schema{
-	//	Attributes: sAttributes(model),
-	//}
-	return *n.schema
+	return *n.schema, diags
 }
 
 // 				Nested Attributes	Nested Blocks
@@ -93,53 +174,408 @@ func New(model any) tfsdk.Schema {
 // Attributes	Yes					No
 // Blocks		Yes					Yes
 
-func rAttribute(model any, tags string, fromSlice bool, level int) *nest {
+// schemaTags returns the tags of the special _ field, which define
+// schema-level things, eg, markdown description, and model-wide defaults.
+func schemaTags(model any) string {
+	e := reflect.ValueOf(model)
+
+	for i := 0; i < e.NumField(); i++ {
+		if !e.Type().Field(i).IsExported() && e.Type().Field(i).Name == "_" && e.Type().Field(i).Type.Kind() == reflect.Struct {
+			return string(e.Type().Field(i).Tag)
+		}
+	}
+
+	return ""
+}
+
+func rAttribute(model any, tags, defaults string, fromSlice bool, level int, fieldPath string, diags *diag.Diagnostics) *nest {
 	if l := leaf(model, tags); l != nil {
 		n := nest{}
-		addAttrOptions(l, tags, reflect.TypeOf(model).String())
+		addAttrOptions(l, tags, baseType(l.Type), fieldPath, diags)
+
+		if r := kindValidators(reflect.TypeOf(model)); r != "" {
+			// problems with the calls would be bugs in kindValidators
+			calls, _ := parseTagValue(r)
+			vals := validators(calls, baseType(l.Type), l.Type, false, tags, fieldPath, diags)
+			l.Validators = append(l.Validators, vals...)
+			checkKindDefaults(l.PlanModifiers, vals, reflect.TypeOf(model), fieldPath, diags)
+		}
 		n.attribute = l
 		return &n
 	}
 
 	switch reflect.ValueOf(model).Kind() {
 	case reflect.Struct:
-		attrs := make(map[string]tfsdk.Attribute)
-		blocks := make(map[string]tfsdk.Block)
-
-		e := reflect.ValueOf(model)
+		if level == 0 {
+			blocks, attrs := rFields(model, defaults, level, fieldPath, diags)
+			return schemaNest(&blocks, &attrs)
+		}
 
-		for i := 0; i < e.NumField(); i++ {
-			if !e.Type().Field(i).IsExported() {
-				continue
+		if tagValueOr(TagNesting, tags, defaults) == TagNestingAttribute {
+			blocks, attrs := rFields(model, nestedDefaults(defaults), level, fieldPath, diags)
+			if len(blocks) > 0 {
+				addTagError(diags, fieldPath, TagNesting, "nested attributes cannot contain blocks")
 			}
 
-			s := snakeCase(e.Type().Field(i).Name, string(e.Type().Field(i).Tag))
-			n := rAttribute(e.Field(i).Interface(), string(e.Type().Field(i).Tag), false, level+1)
-			if n.attribute != nil {
-				attrs[s] = *n.attribute
-			}
-			if n.block != nil {
-				blocks[s] = *n.block
+			nestType := SpecialTypeSingleNested
+			if fromSlice && tagValue(TagCollection, tags) == TagCollectionSet {
+				nestType = SpecialTypeSetNested
+			} else if fromSlice {
+				nestType = SpecialTypeListNested
 			}
+
+			return attrNest(&attrs, nestType, tags, fieldPath, diags)
 		}
 
-		if level == 0 {
-			return schemaNest(&blocks, &attrs)
-		} else {
-			return blockNest(&blocks, &attrs, fromSlice, tags)
+		if fromSlice && tagValue(TagCollection, tags) == TagCollectionSingle {
+			addTagError(diags, fieldPath, TagCollection, "single nesting is only for non-slice struct fields")
 		}
+
+		single := !fromSlice && tagValueOr(TagCollection, tags, defaults) == TagCollectionSingle
+
+		blocks, attrs := rFields(model, defaults, level, fieldPath, diags)
+		return blockNest(&blocks, &attrs, fromSlice, single, tags, fieldPath, diags)
 	case reflect.Slice:
 		if reflect.TypeOf(model).Elem().Kind() != reflect.Struct {
-			panic(fmt.Sprintf("unrecognized slice type: %s", reflect.TypeOf(model).Elem().Kind()))
+			addFieldError(diags, fieldPath, fmt.Sprintf("unrecognized slice type: %s", reflect.TypeOf(model).Elem().Kind()))
+			return &nest{}
 		}
 
-		return rAttribute(reflect.Zero(reflect.TypeOf(model).Elem()).Interface(), tags, true, level+1)
+		return rAttribute(reflect.Zero(reflect.TypeOf(model).Elem()).Interface(), tags, defaults, true, level+1, fieldPath, diags)
 	case reflect.Map:
-		panic("only maps with string keys are supported")
+		if reflect.TypeOf(model).Key().Kind() != reflect.String {
+			addFieldError(diags, fieldPath, "only maps with string keys are supported")
+			return &nest{}
+		}
+
+		if reflect.TypeOf(model).Elem().Kind() != reflect.Struct {
+			addFieldError(diags, fieldPath, fmt.Sprintf("unrecognized map type: %s", reflect.TypeOf(model).Elem().Kind()))
+			return &nest{}
+		}
+
+		// maps of structs can only be nested attributes, never blocks
+		blocks, attrs := rFields(reflect.Zero(reflect.TypeOf(model).Elem()).Interface(), nestedDefaults(defaults), level+1, fieldPath, diags)
+		if len(blocks) > 0 {
+			addTagError(diags, fieldPath, TagNesting, "nested attributes cannot contain blocks")
+		}
+
+		return attrNest(&attrs, SpecialTypeMapNested, tags, fieldPath, diags)
 	default:
 		e := reflect.ValueOf(model)
-		panic(fmt.Sprintf("got unrecognized type: %v", e.Type()))
+		addFieldError(diags, fieldPath, fmt.Sprintf("got unrecognized type: %v", e.Type()))
+		return &nest{}
+	}
+}
+
+// rFields walks the exported fields of a struct, including those of embedded
+// structs, returning the blocks and attributes they become. Two fields with
+// the same schema name are an error.
+func rFields(model any, defaults string, level int, fieldPath string, diags *diag.Diagnostics) (map[string]tfsdk.Block, map[string]tfsdk.Attribute) {
+	attrs := make(map[string]tfsdk.Attribute)
+	blocks := make(map[string]tfsdk.Block)
+
+	e := reflect.ValueOf(model)
+
+	// owners are the field paths of the fields, maybe from different
+	// embedded structs, that have each schema name
+	owners := make(map[string]string)
+
+	for _, f := range modelFields(e.Type()) {
+		fp := joinFieldPath(fieldPath, f.fieldPath)
+		tags := string(f.Tag)
+
+		checkStructTag(tags, fp, diags)
+
+		if isEmbeddedPointer(f.StructField) {
+			addFieldError(diags, fp, fmt.Sprintf("embedded struct pointers are not supported, embed %s instead of %s", f.Type.Elem(), f.Type))
+			continue
+		}
+
+		s := attrName(f.Name, tags, fp, diags)
+		if s == "" {
+			continue
+		}
+
+		if other, ok := owners[s]; ok {
+			addFieldError(diags, fp, fmt.Sprintf("schema name %s is already used by %s", s, other))
+			continue
+		}
+		owners[s] = fp
+
+		if b, ok := timeoutsField(f.StructField, fp, diags); ok {
+			if b != nil {
+				blocks[s] = *b
+			}
+			continue
+		}
+
+		// the zero value of an interface is nil, which has no type to map
+		if f.Type.Kind() == reflect.Interface {
+			addFieldError(diags, fp, fmt.Sprintf("got unrecognized type: %v", f.Type))
+			continue
+		}
+
+		// fields of unexported embedded structs cannot be read, so use zero
+		// values, which have the same types
+		zero := reflect.Zero(f.Type).Interface()
+
+		if a, ok := frameworkLeaf(e, zero, tags, fieldPath, fp, diags); ok {
+			if a != nil {
+				attrs[s] = *a
+			}
+			continue
+		}
+
+		n := rAttribute(zero, tags, defaults, false, level+1, fp, diags)
+		if n.attribute != nil {
+			attrs[s] = *n.attribute
+		}
+		if n.block != nil {
+			blocks[s] = *n.block
+		}
+	}
+
+	crossValidators(e.Type(), attrs, blocks, fieldPath, diags)
+	crossDefaults(e.Type(), attrs, blocks, fieldPath, diags)
+
+	return blocks, attrs
+}
+
+// modelField is a struct field as New sees it, with the fields of embedded
+// structs in place of the embedded struct.
+type modelField struct {
+	reflect.StructField
+
+	// fieldPath is the Go path from the struct, eg, Common.ARN for the field
+	// ARN of the embedded struct Common
+	fieldPath string
+}
+
+// modelFields returns the exported fields of a struct type in order.
+// Embedded structs, exported or not, are flattened into the fields of the
+// struct unless they are named with a tfsdk or snake tag, are framework
+// values or are Timeouts. Embedded struct pointers, exported or not, are
+// returned as fields for rFields to report.
+func modelFields(t reflect.Type) []modelField {
+	fields := []modelField{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		if isEmbedded(f) && f.Type.Kind() == reflect.Struct {
+			for _, ef := range modelFields(f.Type) {
+				ef.Index = append([]int{i}, ef.Index...)
+				ef.fieldPath = joinFieldPath(f.Name, ef.fieldPath)
+				fields = append(fields, ef)
+			}
+			continue
+		}
+
+		if isEmbeddedPointer(f) {
+			fields = append(fields, modelField{StructField: f, fieldPath: f.Name})
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		fields = append(fields, modelField{StructField: f, fieldPath: f.Name})
 	}
+
+	return fields
+}
+
+// isEmbedded reports whether f is an embedded field that is not named with a
+// tfsdk or snake tag, a framework value or Timeouts.
+func isEmbedded(f reflect.StructField) bool {
+	return f.Anonymous && tagValue(TagTfsdk, string(f.Tag)) == "" && tagValue(TagSnakeName, string(f.Tag)) == "" && !isTimeouts(f.Type) && !f.Type.Implements(attrValueType)
+}
+
+// isEmbeddedPointer reports whether f is an embedded struct pointer, eg,
+// *Common, which is not flattened since it may be nil.
+func isEmbeddedPointer(f reflect.StructField) bool {
+	return isEmbedded(f) && f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct
+}
+
+// siblingNames maps the Go and schema names of a struct's fields to their
+// schema names.
+func siblingNames(t reflect.Type) map[string]string {
+	names := make(map[string]string)
+
+	for _, f := range modelFields(t) {
+		// problems with names are reported by rFields
+		s := attrName(f.Name, string(f.Tag), f.Name, &diag.Diagnostics{})
+		if s == "" {
+			continue
+		}
+
+		names[f.Name] = s
+		names[s] = s
+	}
+
+	return names
+}
+
+// crossValidators adds the conflicts, exactlyoneof, atleastoneof and
+// alsorequires validators, which refer to sibling fields by Go or schema
+// name, once all the fields of a struct are known.
+func crossValidators(t reflect.Type, attrs map[string]tfsdk.Attribute, blocks map[string]tfsdk.Block, fieldPath string, diags *diag.Diagnostics) {
+	siblings := siblingNames(t)
+
+	for _, f := range modelFields(t) {
+		tags := string(f.Tag)
+		fp := joinFieldPath(fieldPath, f.fieldPath)
+
+		// problems with the tag are reported by addAttrOptions
+		calls, _ := parseTagValue(tagValue(TagValidators, tags))
+
+		vals := []tfsdk.AttributeValidator{}
+
+		for _, c := range calls {
+			switch c.name {
+			case TagValidatorConflicts, TagValidatorExactlyOneOf, TagValidatorAtLeastOneOf, TagValidatorAlsoRequires:
+			default:
+				continue
+			}
+
+			if len(c.args) == 0 {
+				addTagError(diags, fp, TagValidators, fmt.Sprintf("%s requires sibling field names at column %d", c.name, c.pos+1))
+				continue
+			}
+
+			names := []string{}
+			for _, a := range c.args {
+				n, ok := siblings[a.value]
+				if !ok || a.key != "" || a.call != nil {
+					addTagError(diags, fp, TagValidators, fmt.Sprintf("%s refers to %s, which is not a sibling field, at column %d", c.name, a.value, a.pos+1))
+					continue
+				}
+
+				if n == siblings[f.Name] {
+					addTagError(diags, fp, TagValidators, fmt.Sprintf("%s refers to the field itself at column %d", c.name, a.pos+1))
+					continue
+				}
+
+				names = append(names, n)
+			}
+
+			if len(names) == len(c.args) {
+				vals = append(vals, crossValidator(c.name, names))
+			}
+		}
+
+		if len(vals) == 0 {
+			continue
+		}
+
+		s := siblings[f.Name]
+
+		if a, ok := attrs[s]; ok {
+			a.Validators = append(a.Validators, vals...)
+			attrs[s] = a
+		}
+
+		if b, ok := blocks[s]; ok {
+			b.Validators = append(b.Validators, vals...)
+			blocks[s] = b
+		}
+	}
+}
+
+// crossDefaults adds the default(attr=name) plan modifiers, which copy a
+// sibling field by Go or schema name, once all the fields of a struct are
+// known.
+func crossDefaults(t reflect.Type, attrs map[string]tfsdk.Attribute, blocks map[string]tfsdk.Block, fieldPath string, diags *diag.Diagnostics) {
+	siblings := siblingNames(t)
+
+	// fieldType returns the type of the attribute or block with the schema
+	// name n
+	fieldType := func(n string) attr.Type {
+		if a, ok := attrs[n]; ok {
+			return a.FrameworkType()
+		}
+
+		if b, ok := blocks[n]; ok {
+			return b.Type()
+		}
+
+		return nil
+	}
+
+	for _, f := range modelFields(t) {
+		tags := string(f.Tag)
+		fp := joinFieldPath(fieldPath, f.fieldPath)
+
+		// problems with the tag are reported by pMods
+		calls, _ := parseTagValue(tagValue(TagPlanModifiers, tags))
+
+		c, ok := findCall(calls, TagPlanModifierDefault)
+		if !ok || len(c.args) != 1 || c.args[0].key != TagDefaultAttr {
+			continue
+		}
+
+		a := c.args[0]
+		s := siblings[f.Name]
+
+		// defaults on blocks are reported by pMods
+		if _, ok := blocks[s]; ok {
+			continue
+		}
+
+		n, ok := siblings[a.value]
+		if !ok {
+			addTagError(diags, fp, TagPlanModifiers, fmt.Sprintf("%s refers to %s, which is not a sibling field, at column %d", TagPlanModifierDefault, a.value, a.pos+1))
+			continue
+		}
+
+		if n == s {
+			addTagError(diags, fp, TagPlanModifiers, fmt.Sprintf("%s refers to the field itself at column %d", TagPlanModifierDefault, a.pos+1))
+			continue
+		}
+
+		st := fieldType(s)
+		nt := fieldType(n)
+		if st == nil || nt == nil {
+			continue
+		}
+
+		if !nt.Equal(st) {
+			addTagError(diags, fp, TagPlanModifiers, fmt.Sprintf("%s refers to %s, a %s, but the field is a %s, at column %d", TagPlanModifierDefault, a.value, nt, st, a.pos+1))
+			continue
+		}
+
+		if a, ok := attrs[s]; ok {
+			a.PlanModifiers = append(a.PlanModifiers, defaultFromAttribute(path.MatchRelative().AtParent().AtName(n)))
+			attrs[s] = a
+		}
+	}
+}
+
+// crossValidator returns a validator relating an attribute to the siblings
+// with the given schema names.
+func crossValidator(name string, siblings []string) tfsdk.AttributeValidator {
+	exprs := []path.Expression{}
+	for _, s := range siblings {
+		exprs = append(exprs, path.MatchRelative().AtParent().AtName(s))
+	}
+
+	switch name {
+	case TagValidatorConflicts:
+		return schemavalidator.ConflictsWith(exprs...)
+	case TagValidatorExactlyOneOf:
+		return schemavalidator.ExactlyOneOf(exprs...)
+	case TagValidatorAtLeastOneOf:
+		return schemavalidator.AtLeastOneOf(exprs...)
+	}
+
+	return schemavalidator.AlsoRequires(exprs...)
+}
+
+// nestedDefaults returns the defaults for fields inside nested attributes,
+// which can only contain further nested attributes.
+func nestedDefaults(defaults string) string {
+	return fmt.Sprintf(`%s:"%s" %s`, TagNesting, TagNestingAttribute, defaults)
 }
 
 func schemaNest(blocks *map[string]tfsdk.Block, attrs *map[string]tfsdk.Attribute) *nest {
@@ -158,7 +594,7 @@ func schemaNest(blocks *map[string]tfsdk.Block, attrs *map[string]tfsdk.Attribut
 	return &n
 }
 
-func blockNest(blocks *map[string]tfsdk.Block, attrs *map[string]tfsdk.Attribute, slice bool, tags string) *nest {
+func blockNest(blocks *map[string]tfsdk.Block, attrs *map[string]tfsdk.Attribute, slice, single bool, tags, fieldPath string, diags *diag.Diagnostics) *nest {
 	b := &tfsdk.Block{}
 
 	if len(*blocks) > 0 {
@@ -169,18 +605,39 @@ func blockNest(blocks *map[string]tfsdk.Block, attrs *map[string]tfsdk.Attribute
 		b.Attributes = *attrs
 	}
 
-	addBlockOptions(b, slice, tags)
+	addBlockOptions(b, slice, single, tags, fieldPath, diags)
 
 	n := nest{}
 	n.block = b
 	return &n
 }
 
-func schemaLevelOptions(schm *tfsdk.Schema, tags string) {
+func attrNest(attrs *map[string]tfsdk.Attribute, nestType, tags, fieldPath string, diags *diag.Diagnostics) *nest {
+	a := &tfsdk.Attribute{}
+
+	switch nestType {
+	case SpecialTypeListNested:
+		a.Attributes = tfsdk.ListNestedAttributes(*attrs)
+	case SpecialTypeMapNested:
+		a.Attributes = tfsdk.MapNestedAttributes(*attrs)
+	case SpecialTypeSetNested:
+		a.Attributes = tfsdk.SetNestedAttributes(*attrs)
+	default:
+		a.Attributes = tfsdk.SingleNestedAttributes(*attrs)
+	}
+
+	addAttrOptions(a, tags, nestType, fieldPath, diags)
+
+	n := nest{}
+	n.attribute = a
+	return &n
+}
+
+func schemaLevelOptions(schm *tfsdk.Schema, tags string, diags *diag.Diagnostics) {
 	if v := tagValue(TagVersion, tags); v != "" {
 		vi, err := strconv.ParseInt(v, 10, 0)
 		if err != nil {
-			panic(fmt.Sprintf("version must be an int, not %s: %s", v, err))
+			addTagError(diags, "_", TagVersion, fmt.Sprintf("version must be an int, not %s: %s", v, err))
 		}
 		schm.Version = vi
 	}
@@ -198,116 +655,356 @@ func schemaLevelOptions(schm *tfsdk.Schema, tags string) {
 	}
 }
 
+// leaf returns the attribute for a field of a scalar type, a pointer to one
+// or a slice or string-keyed map of them, or nil if the field is not one.
+// Pointers are the same as what they point to since null is always allowed.
 func leaf(model any, tags string) *tfsdk.Attribute {
 	a := tfsdk.Attribute{}
 
-	switch reflect.TypeOf(model).String() {
-	case "types.Bool", "bool":
-		a.Type = types.BoolType
-		return &a
-	case "types.Float64", "float", "float64":
-		a.Type = types.Float64Type
-		return &a
-	case "types.Int64", "int64", "int":
-		a.Type = types.Int64Type
-		return &a
-	case "types.Number":
-		a.Type = types.NumberType
-		return &a
-	case "types.String", "string":
-		a.Type = types.StringType
-		return &a
-	case "map[string]types.Bool", "map[string]bool":
-		a.Type = types.MapType{
-			ElemType: types.BoolType,
-		}
+	if t := collectionType(reflect.TypeOf(model), tags); t != nil {
+		a.Type = t
 		return &a
-	case "map[string]types.Float64", "map[string]float", "map[string]float64":
-		a.Type = types.MapType{
-			ElemType: types.Float64Type,
-		}
-		return &a
-	case "map[string]types.Int64", "map[string]int64", "map[string]int":
-		a.Type = types.MapType{
-			ElemType: types.Int64Type,
-		}
-		return &a
-	case "map[string]types.Number":
-		a.Type = types.MapType{
-			ElemType: types.NumberType,
-		}
+	}
+
+	goType := reflect.TypeOf(model)
+
+	if t := scalarType(goType); t != nil {
+		a.Type = t
 		return &a
-	case "map[string]types.String", "map[string]string":
-		a.Type = types.MapType{
-			ElemType: types.StringType,
+	}
+
+	switch goType.Kind() {
+	case reflect.Slice:
+		et := scalarType(goType.Elem())
+		if et == nil {
+			return nil
 		}
-		return &a
-	case "[]types.Bool", "[]bool":
+
 		if tagValue(TagCollection, tags) == TagCollectionSet {
 			a.Type = types.SetType{
-				ElemType: types.BoolType,
+				ElemType: et,
 			}
 			return &a
 		}
 
 		a.Type = types.ListType{
-			ElemType: types.BoolType,
+			ElemType: et,
 		}
 		return &a
-	case "[]types.Float64", "[]float", "[]float64":
-		if tagValue(TagCollection, tags) == TagCollectionSet {
-			a.Type = types.SetType{
-				ElemType: types.Float64Type,
-			}
-			return &a
+	case reflect.Map:
+		if goType.Key().Kind() != reflect.String {
+			return nil
 		}
 
-		a.Type = types.ListType{
-			ElemType: types.Float64Type,
+		et := scalarType(goType.Elem())
+		if et == nil {
+			return nil
+		}
+
+		a.Type = types.MapType{
+			ElemType: et,
 		}
 		return &a
-	case "[]types.Int64", "[]int64", "[]int":
-		if tagValue(TagCollection, tags) == TagCollectionSet {
-			a.Type = types.SetType{
-				ElemType: types.Int64Type,
-			}
-			return &a
+	}
+
+	return nil
+}
+
+// Go types that are strings in a particular format in the schema.
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	bytesType    = reflect.TypeOf([]byte(nil))
+)
+
+// scalarType returns the attr.Type for a framework scalar type, a Go type of
+// a scalar kind, such as uint16 or `type Region string`, time.Duration,
+// time.Time or []byte, which are strings, or a pointer to any of them, or nil
+// for anything else.
+func scalarType(goType reflect.Type) attr.Type {
+	if goType.Kind() == reflect.Pointer {
+		goType = goType.Elem()
+	}
+
+	switch goType {
+	case durationType, timeType, bytesType:
+		return types.StringType
+	case reflect.TypeOf(types.Bool{}):
+		return types.BoolType
+	case reflect.TypeOf(types.Float64{}):
+		return types.Float64Type
+	case reflect.TypeOf(types.Int64{}):
+		return types.Int64Type
+	case reflect.TypeOf(types.Number{}):
+		return types.NumberType
+	case reflect.TypeOf(types.String{}):
+		return types.StringType
+	}
+
+	switch goType.Kind() {
+	case reflect.Bool:
+		return types.BoolType
+	case reflect.Float32, reflect.Float64:
+		return types.Float64Type
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return types.Int64Type
+	case reflect.String:
+		return types.StringType
+	}
+
+	return nil
+}
+
+// kindValidators returns the valid tag calls implied by a Go type: a range,
+// eg, between(0,255) for a uint8, that keeps integer kinds narrower than
+// int64, and float32, in range, or the format of time.Duration (duration), time.Time
+// (rfc3339) or []byte (base64). They are wrapped in each(...) for slices and
+// maps. Registered types are left to their own validators.
+func kindValidators(goType reflect.Type) string {
+	if registeredType(goType) != nil {
+		return ""
+	}
+
+	if goType.Kind() == reflect.Pointer {
+		goType = goType.Elem()
+	}
+
+	switch goType {
+	case durationType:
+		return TagValidatorDuration
+	case timeType:
+		return TagValidatorRFC3339
+	case bytesType:
+		return TagValidatorBase64
+	}
+
+	if goType.Kind() == reflect.Slice || goType.Kind() == reflect.Map {
+		if r := kindValidators(goType.Elem()); r != "" {
+			return fmt.Sprintf("%s(%s)", TagValidatorEach, r)
 		}
+		return ""
+	}
+
+	if goType.Kind() == reflect.Float32 {
+		max := strconv.FormatFloat(math.MaxFloat32, 'g', -1, 64)
+		return fmt.Sprintf("%s(-%s,%s)", TagValidatorBetween, max, max)
+	}
+
+	var min, max int64
+
+	switch goType.Kind() {
+	case reflect.Int8:
+		min, max = math.MinInt8, math.MaxInt8
+	case reflect.Int16:
+		min, max = math.MinInt16, math.MaxInt16
+	case reflect.Int32:
+		min, max = math.MinInt32, math.MaxInt32
+	case reflect.Uint8:
+		max = math.MaxUint8
+	case reflect.Uint16:
+		max = math.MaxUint16
+	case reflect.Uint32:
+		max = math.MaxUint32
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		// larger values do not fit in an int64 anyway
+		max = math.MaxInt64
+	default:
+		return ""
+	}
+
+	return fmt.Sprintf("%s(%d,%d)", TagValidatorBetween, min, max)
+}
+
+// frameworkLeaf handles types.List, types.Set, types.Map and types.Object
+// fields, which need an elem tag to say what they hold, eg, elem:"string" or
+// elem:"list(object(endpoint))". Object elements refer by name to a companion
+// struct field, usually unexported, in the parent struct. The bool reports
+// whether the field is one of these types, even if there were problems.
+func frameworkLeaf(parent reflect.Value, model any, tags, parentPath, fieldPath string, diags *diag.Diagnostics) (*tfsdk.Attribute, bool) {
+	modelType := reflect.TypeOf(model).String()
+
+	switch modelType {
+	case "types.List", "types.Set", "types.Map", "types.Object":
+	default:
+		return nil, false
+	}
+
+	a := &tfsdk.Attribute{}
 
+	spec := tagValue(TagElem, tags)
+	if spec == "" {
+		addTagError(diags, fieldPath, TagElem, fmt.Sprintf("%s requires an %s tag", modelType, TagElem))
+		return nil, true
+	}
+
+	et := elemType(spec, parent, parentPath, fieldPath, diags)
+	if et == nil {
+		return nil, true
+	}
+
+	switch modelType {
+	case "types.List":
 		a.Type = types.ListType{
-			ElemType: types.Int64Type,
+			ElemType: et,
 		}
-		return &a
-	case "[]types.Number":
-		if tagValue(TagCollection, tags) == TagCollectionSet {
-			a.Type = types.SetType{
-				ElemType: types.NumberType,
-			}
-			return &a
+	case "types.Set":
+		a.Type = types.SetType{
+			ElemType: et,
 		}
+	case "types.Map":
+		a.Type = types.MapType{
+			ElemType: et,
+		}
+	case "types.Object":
+		ot, ok := et.(types.ObjectType)
+		if !ok {
+			addTagError(diags, fieldPath, TagElem, fmt.Sprintf("types.Object requires %s(...), got %s", TagElemObject, spec))
+			return nil, true
+		}
+		a.Type = ot
+	}
 
-		a.Type = types.ListType{
-			ElemType: types.NumberType,
+	addAttrOptions(a, tags, baseType(a.Type), fieldPath, diags)
+
+	return a, true
+}
+
+// elemType parses an elem tag value into an attr.Type.
+func elemType(spec string, parent reflect.Value, parentPath, fieldPath string, diags *diag.Diagnostics) attr.Type {
+	kind, arg := spec, ""
+	if i := strings.Index(spec, "("); i > 0 && strings.HasSuffix(spec, ")") {
+		kind, arg = spec[:i], spec[i+1:len(spec)-1]
+	}
+
+	switch kind {
+	case TagElemBool:
+		return types.BoolType
+	case TagElemFloat64:
+		return types.Float64Type
+	case TagElemInt64:
+		return types.Int64Type
+	case TagElemNumber:
+		return types.NumberType
+	case TagElemString:
+		return types.StringType
+	case TagElemList, TagElemMap, TagElemSet:
+		if arg == "" {
+			addTagError(diags, fieldPath, TagElem, fmt.Sprintf("%s requires an element type, eg, %s(string)", kind, kind))
+			return nil
 		}
-		return &a
-	case "[]types.String", "[]string":
-		if tagValue(TagCollection, tags) == TagCollectionSet {
-			a.Type = types.SetType{
-				ElemType: types.StringType,
-			}
-			return &a
+
+		et := elemType(arg, parent, parentPath, fieldPath, diags)
+		if et == nil {
+			return nil
+		}
+
+		switch kind {
+		case TagElemList:
+			return types.ListType{ElemType: et}
+		case TagElemMap:
+			return types.MapType{ElemType: et}
+		default:
+			return types.SetType{ElemType: et}
+		}
+	case TagElemObject:
+		sf, ok := parent.Type().FieldByName(arg)
+		if !ok || sf.Type.Kind() != reflect.Struct {
+			addTagError(diags, fieldPath, TagElem, fmt.Sprintf("%s(%s) requires a companion struct field named %s", TagElemObject, arg, arg))
+			return nil
 		}
 
-		a.Type = types.ListType{
-			ElemType: types.StringType,
+		_, attrs := rFields(reflect.Zero(sf.Type).Interface(), nestedDefaults(""), 1, joinFieldPath(parentPath, arg), diags)
+
+		ot := types.ObjectType{
+			AttrTypes: make(map[string]attr.Type),
 		}
-		return &a
+		for k, v := range attrs {
+			ot.AttrTypes[k] = v.FrameworkType()
+		}
+
+		return ot
 	}
 
+	addTagError(diags, fieldPath, TagElem, fmt.Sprintf("unrecognized element type: %s", spec))
 	return nil
 }
 
-func addAttrOptions(a *tfsdk.Attribute, tags, attrType string) {
+// baseType returns the name of the framework type that validators and
+// defaults use for an attribute type. Custom types go by their underlying
+// Terraform type.
+func baseType(t attr.Type) string {
+	switch t.(type) {
+	case types.ListType:
+		return "types.ListType"
+	case types.MapType:
+		return "types.MapType"
+	case types.ObjectType:
+		return "types.ObjectType"
+	case types.SetType:
+		return "types.SetType"
+	}
+
+	switch t {
+	case types.BoolType:
+		return "types.Bool"
+	case types.Float64Type:
+		return "types.Float64"
+	case types.Int64Type:
+		return "types.Int64"
+	case types.NumberType:
+		return "types.Number"
+	case types.StringType:
+		return "types.String"
+	}
+
+	tt := t.TerraformType(context.Background())
+
+	switch {
+	case tt.Is(tftypes.Bool):
+		return "types.Bool"
+	case tt.Is(tftypes.Number):
+		return "types.Number"
+	case tt.Is(tftypes.String):
+		return "types.String"
+	case tt.Is(tftypes.List{}):
+		return "types.ListType"
+	case tt.Is(tftypes.Map{}):
+		return "types.MapType"
+	case tt.Is(tftypes.Object{}):
+		return "types.ObjectType"
+	case tt.Is(tftypes.Set{}):
+		return "types.SetType"
+	}
+
+	return ""
+}
+
+// isFrameworkType reports whether t is one of the framework's own primitive
+// types rather than a custom type.
+func isFrameworkType(t attr.Type) bool {
+	switch t {
+	case types.BoolType, types.Float64Type, types.Int64Type, types.NumberType, types.StringType:
+		return true
+	}
+
+	return false
+}
+
+// convertValue converts a framework value into a value of another attr.Type
+// with the same underlying Terraform type.
+func convertValue(v attr.Value, t attr.Type) (attr.Value, error) {
+	ctx := context.Background()
+
+	tv, err := v.ToTerraformValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.ValueFromTerraform(ctx, tv)
+}
+
+func addAttrOptions(a *tfsdk.Attribute, tags, attrType, fieldPath string, diags *diag.Diagnostics) {
 	if tagValue(TagComputed, tags) == TagTrue {
 		a.Computed = true
 	}
@@ -340,19 +1037,26 @@ func addAttrOptions(a *tfsdk.Attribute, tags, attrType string) {
 		a.MarkdownDescription = v
 	}
 
-	if v := tagValue(TagPlanModifiers, tags); v != "" {
-		a.PlanModifiers = pMods(v, attrType)
+	if calls := tagCalls(TagPlanModifiers, tags, fieldPath, diags); len(calls) > 0 {
+		a.PlanModifiers = pMods(calls, attrType, a.FrameworkType(), fieldPath, diags)
+		checkDefaults(a.PlanModifiers, a.FrameworkType(), fieldPath, diags)
 	}
 
-	if v := tagValue(TagValidators, tags); v != "" {
-		a.Validators = validators(v, attrType, false, tags)
+	if calls := tagCalls(TagValidators, tags, fieldPath, diags); len(calls) > 0 {
+		a.Validators = validators(calls, attrType, a.Type, false, tags, fieldPath, diags)
 	}
 }
 
-func addBlockOptions(b *tfsdk.Block, slice bool, tags string) {
-	if tagValue(TagCollection, tags) == TagCollectionSet {
+func addBlockOptions(b *tfsdk.Block, slice, single bool, tags, fieldPath string, diags *diag.Diagnostics) {
+	blockType := SpecialTypeBlock
+
+	switch {
+	case single:
+		b.NestingMode = tfsdk.BlockNestingModeSingle
+		blockType = SpecialTypeSingleBlock
+	case tagValue(TagCollection, tags) == TagCollectionSet:
 		b.NestingMode = tfsdk.BlockNestingModeSet
-	} else {
+	default:
 		b.NestingMode = tfsdk.BlockNestingModeList
 	}
 
@@ -368,75 +1072,409 @@ func addBlockOptions(b *tfsdk.Block, slice bool, tags string) {
 		b.MarkdownDescription = v
 	}
 
-	if v := tagValue(TagPlanModifiers, tags); v != "" {
-		b.PlanModifiers = pMods(v, SpecialTypeBlock)
+	if calls := tagCalls(TagPlanModifiers, tags, fieldPath, diags); len(calls) > 0 {
+		b.PlanModifiers = pMods(calls, blockType, b.Type(), fieldPath, diags)
+		checkDefaults(b.PlanModifiers, b.Type(), fieldPath, diags)
 	}
 
 	// called no matter what since some are added even when not explicitly requested
-	b.Validators = validators(tagValue(TagValidators, tags), SpecialTypeBlock, slice, tags)
+	// (except for single blocks, which have no size to validate)
+	b.Validators = validators(tagCalls(TagValidators, tags, fieldPath, diags), blockType, nil, slice, tags, fieldPath, diags)
 }
 
-func pMods(tagValue, attrType string) []tfsdk.AttributePlanModifier {
+func pMods(calls []tagCall, attrType string, t attr.Type, fieldPath string, diags *diag.Diagnostics) []tfsdk.AttributePlanModifier {
 	pm := []tfsdk.AttributePlanModifier{}
 
-	if hasTagArg(TagPlanModifierReplace, tagValue) {
-		pm = append(pm, resource.RequiresReplace())
+	if c, ok := findCall(calls, TagPlanModifierReplace); ok {
+		if r := replacePlanModifier(c, attrType, fieldPath, diags); r != nil {
+			pm = append(pm, r)
+		}
 	}
 
-	if hasTagArg(TagPlanModifierUSFU, tagValue) {
+	if hasCall(calls, TagPlanModifierUSFU) {
 		pm = append(pm, resource.UseStateForUnknown())
 	}
 
-	if hasTagArg(TagPlanModifierDefault, tagValue) {
-		dv := tagArgs(TagPlanModifierDefault, tagValue)
-		switch attrType {
-		case "types.Bool", "bool":
-			b, err := strconv.ParseBool(dv)
+	if c, ok := findCall(calls, TagPlanModifierDefault); ok && (attrType == SpecialTypeBlock || attrType == SpecialTypeSingleBlock) {
+		// Terraform requires planned blocks to match the configuration
+		addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s is not supported on blocks, which Terraform cannot plan when not configured, at column %d; use a nested attribute, with nesting:\"attribute\" and computed:\"true\", instead", TagPlanModifierDefault, c.pos+1))
+	} else if ok && len(c.args) == 1 && c.args[0].key != "" {
+		switch a := c.args[0]; a.key {
+		case TagDefaultEnv:
+			pm = append(pm, defaultFromEnv(a.value))
+		case TagDefaultAttr:
+			// added by crossDefaults, which knows the sibling fields
+		default:
+			addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s takes a value, %s=NAME or %s=field, not %s=, at column %d", TagPlanModifierDefault, TagDefaultEnv, TagDefaultAttr, a.key, a.pos+1))
+		}
+	} else if ok {
+		if len(c.args) > 1 {
+			addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s requires 1 value, quoted if it has commas or parentheses, at column %d", TagPlanModifierDefault, c.pos+1))
+			return pm
+		}
+
+		dv := ""
+		if len(c.args) == 1 {
+			dv = c.args[0].value
+		}
+
+		v, err := defaultValue(dv, t)
+		if err != nil {
+			addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("default value (%s) %s", dv, err))
+		}
+
+		if v != nil {
+			pm = append(pm, DefaultValue(v))
+		}
+	}
+
+	pm = append(pm, registeredPlanModifiers(calls, t, fieldPath, diags)...)
+
+	return pm
+}
+
+// defaultValue parses the value of default(...) as a value of type t:
+// scalars as they are and lists, sets, maps, objects and blocks from
+// literals, eg, [a,b] or {k=v}.
+func defaultValue(dv string, t attr.Type) (attr.Value, error) {
+	if t == nil {
+		return nil, errors.New("has no type")
+	}
+
+	ft, err := frameworkType(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isFrameworkType(ft) {
+		l, err := parseLiteral(dv)
+		if err != nil {
+			return nil, fmt.Errorf("is not a literal: %s", err)
+		}
+
+		v, err := literalValue(l, t)
+		if err != nil {
+			return nil, fmt.Errorf("is not a %s: %s", t, err)
+		}
+
+		return v, nil
+	}
+
+	v, err := scalarValue(dv, ft)
+	if err != nil {
+		return nil, err
+	}
+
+	if isCustomType(t) {
+		// custom types get the default as their own value type
+		if v, err = convertValue(v, t); err != nil {
+			return nil, fmt.Errorf("is not a %s: %s", t, err)
+		}
+	}
+
+	return v, nil
+}
+
+// scalarValue parses s as a value of the framework scalar type t.
+func scalarValue(s string, t attr.Type) (attr.Value, error) {
+	switch t {
+	case types.BoolType:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("is not a bool: %s", err)
+		}
+
+		return types.Bool{Value: b}, nil
+	case types.Float64Type:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("is not a number: %s", err)
+		}
+
+		return types.Float64{Value: f}, nil
+	case types.Int64Type:
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("is not a number: %s", err)
+		}
+
+		return types.Int64{Value: i}, nil
+	case types.NumberType:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("is not a number: %s", err)
+		}
+
+		return types.Number{Value: big.NewFloat(f)}, nil
+	case types.StringType:
+		return types.String{Value: s}, nil
+	}
+
+	return nil, fmt.Errorf("cannot be a %s", t)
+}
+
+// frameworkType returns t if it is a framework type or, for a custom type,
+// the framework type with the same Terraform type.
+func frameworkType(t attr.Type) (attr.Type, error) {
+	if !isCustomType(t) {
+		return t, nil
+	}
+
+	ft, err := frameworkTypeOf(t.TerraformType(context.Background()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot be a %s: %s", t, err)
+	}
+
+	return ft, nil
+}
+
+// isCustomType reports whether t is a custom type rather than one of the
+// framework's.
+func isCustomType(t attr.Type) bool {
+	switch t.(type) {
+	case types.ListType, types.MapType, types.ObjectType, types.SetType:
+		return false
+	}
+
+	return !isFrameworkType(t)
+}
+
+// checkDefaults reports defaults, including those from registered plan
+// modifiers, that are not of type t, the type of their attribute or block,
+// which the framework rejects when planning.
+func checkDefaults(pms []tfsdk.AttributePlanModifier, t attr.Type, fieldPath string, diags *diag.Diagnostics) {
+	ctx := context.Background()
+
+	for _, pm := range pms {
+		dv, ok := pm.(*defaultValuePlanModifier)
+		if !ok {
+			continue
+		}
+
+		vt := dv.DefaultValue.Type(ctx)
+		if vt.Equal(t) {
+			continue
+		}
+
+		// custom types' values may give the type they wrap
+		if (isCustomType(t) || isCustomType(vt)) && vt.TerraformType(ctx).Equal(t.TerraformType(ctx)) {
+			continue
+		}
+
+		addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("default value (%s) is a %s but the attribute is a %s", dv.DefaultValue, vt, t))
+	}
+}
+
+// checkKindDefaults adds errors for default values that the validators
+// implied by a Go type, from kindValidators, reject, eg, default(300) for a
+// uint8, which the plan could not be decoded into.
+func checkKindDefaults(pms []tfsdk.AttributePlanModifier, vals []tfsdk.AttributeValidator, goType reflect.Type, fieldPath string, diags *diag.Diagnostics) {
+	ctx := context.Background()
+
+	for _, pm := range pms {
+		dv, ok := pm.(*defaultValuePlanModifier)
+		if !ok {
+			continue
+		}
+
+		for _, v := range vals {
+			req := tfsdk.ValidateAttributeRequest{
+				AttributePath:           path.Empty(),
+				AttributePathExpression: path.Empty().Expression(),
+				AttributeConfig:         dv.DefaultValue,
+			}
+			res := &tfsdk.ValidateAttributeResponse{}
+
+			v.Validate(ctx, req, res)
+
+			if res.Diagnostics.HasError() {
+				addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("default value (%s) is invalid for a %s: %s", dv.DefaultValue, goType, v.Description(ctx)))
+			}
+		}
+	}
+}
+
+// literalValue converts a literal into a value of type t.
+func literalValue(l literal, t attr.Type) (attr.Value, error) {
+	ft, err := frameworkType(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if isCustomType(t) {
+		// custom types get the value as their own value type
+		v, err := literalValue(l, ft)
+		if err != nil {
+			return nil, err
+		}
+
+		return convertValue(v, t)
+	}
+
+	switch tt := t.(type) {
+	case types.ListType, types.SetType:
+		if l.kind != literalList {
+			return nil, errors.New("expected a list, eg, [a,b]")
+		}
+
+		et := tt.(attr.TypeWithElementType).ElementType()
+
+		elems := []attr.Value{}
+		for _, item := range l.items {
+			v, err := literalValue(item, et)
 			if err != nil {
-				panic(fmt.Sprintf("default value (%s) is not a bool: %s", dv, err))
+				return nil, err
+			}
+
+			if _, ok := tt.(types.SetType); ok {
+				for _, e := range elems {
+					if e.Equal(v) {
+						return nil, fmt.Errorf("duplicate set item %s", v)
+					}
+				}
 			}
 
-			pm = append(pm, DefaultValue(types.Bool{Value: b}))
-		case "types.Float64", "float", "float64":
-			f, err := strconv.ParseFloat(dv, 64)
+			elems = append(elems, v)
+		}
+
+		if _, ok := tt.(types.SetType); ok {
+			return types.Set{ElemType: et, Elems: elems}, nil
+		}
+
+		return types.List{ElemType: et, Elems: elems}, nil
+	case types.MapType:
+		if l.kind != literalMap {
+			return nil, errors.New("expected a map, eg, {k=v}")
+		}
+
+		elems := map[string]attr.Value{}
+		for i, item := range l.items {
+			v, err := literalValue(item, tt.ElemType)
 			if err != nil {
-				panic(fmt.Sprintf("default value (%s) is not a number: %s", dv, err))
+				return nil, err
+			}
+
+			elems[l.keys[i]] = v
+		}
+
+		return types.Map{ElemType: tt.ElemType, Elems: elems}, nil
+	case types.ObjectType:
+		if l.kind != literalMap {
+			return nil, errors.New("expected attributes, eg, {name=v}")
+		}
+
+		attrs := map[string]attr.Value{}
+		for i, item := range l.items {
+			at, ok := tt.AttrTypes[l.keys[i]]
+			if !ok {
+				return nil, fmt.Errorf("no attribute %s", l.keys[i])
 			}
 
-			pm = append(pm, DefaultValue(types.Float64{Value: f}))
-		case "types.Int64", "int64":
-			i, err := strconv.ParseInt(dv, 10, 64)
+			v, err := literalValue(item, at)
 			if err != nil {
-				panic(fmt.Sprintf("default value (%s) is not a number: %s", dv, err))
+				return nil, err
+			}
+
+			attrs[l.keys[i]] = v
+		}
+
+		// attributes left out are null
+		for name, at := range tt.AttrTypes {
+			if _, ok := attrs[name]; ok {
+				continue
 			}
 
-			pm = append(pm, DefaultValue(types.Int64{Value: i}))
-		case "types.Number", "int":
-			f, err := strconv.ParseFloat(dv, 64)
+			v, err := at.ValueFromTerraform(context.Background(), tftypes.NewValue(at.TerraformType(context.Background()), nil))
 			if err != nil {
-				panic(fmt.Sprintf("default value (%s) is not a number: %s", dv, err))
+				return nil, err
 			}
 
-			pm = append(pm, DefaultValue(types.Number{Value: big.NewFloat(f)}))
-		case "types.String", "string":
-			pm = append(pm, DefaultValue(types.String{Value: dv}))
+			attrs[name] = v
 		}
+
+		return types.Object{AttrTypes: tt.AttrTypes, Attrs: attrs}, nil
 	}
 
-	return pm
+	if l.kind != literalScalar {
+		return nil, fmt.Errorf("expected a %s", t)
+	}
+
+	v, err := scalarValue(l.text, t)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s", quoteLiteral(l.text), err)
+	}
+
+	return v, nil
+}
+
+// replacePlanModifier handles replace, which always forces a new resource,
+// and replace(ifset), replace(ifconfigured), replace(decrease) and
+// replace(func=name), which only do so under a condition.
+func replacePlanModifier(c tagCall, attrType, fieldPath string, diags *diag.Diagnostics) tfsdk.AttributePlanModifier {
+	if len(c.args) == 0 {
+		return resource.RequiresReplace()
+	}
+
+	if len(c.args) > 1 {
+		addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s takes 1 condition, got %d, at column %d", TagPlanModifierReplace, len(c.args), c.pos+1))
+		return nil
+	}
+
+	a := c.args[0]
+
+	if a.key == TagReplaceFunc {
+		f, desc := registeredReplaceIf(a.value)
+		if f == nil {
+			addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s function %s is not registered at column %d", TagPlanModifierReplace, a.value, a.pos+1))
+			return nil
+		}
+
+		return resource.RequiresReplaceIf(f, desc, desc)
+	}
+
+	if a.key != "" {
+		addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s does not take %s= at column %d", TagPlanModifierReplace, a.key, a.pos+1))
+		return nil
+	}
+
+	switch a.value {
+	case TagReplaceIfSet:
+		desc := "If the value of this attribute changes after it is set, Terraform will destroy and recreate the resource."
+		return resource.RequiresReplaceIf(replaceIfSet, desc, desc)
+	case TagReplaceIfConfigured:
+		desc := "If the value of this attribute changes to another configured value, Terraform will destroy and recreate the resource."
+		return resource.RequiresReplaceIf(replaceIfConfigured, desc, desc)
+	case TagReplaceDecrease:
+		desc := "If the value of this attribute decreases, Terraform will destroy and recreate the resource."
+
+		switch attrType {
+		case "types.Float64", "types.Int64", "types.Number":
+		case "types.ListType", "types.MapType", "types.SetType":
+			desc = "If the number of items in this attribute decreases, Terraform will destroy and recreate the resource."
+		default:
+			addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s(%s) is only for numbers, lists, sets and maps at column %d", TagPlanModifierReplace, TagReplaceDecrease, a.pos+1))
+			return nil
+		}
+
+		return resource.RequiresReplaceIf(replaceIfDecrease, desc, desc)
+	}
+
+	addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s condition must be %s, %s, %s or %s=name, got %s at column %d", TagPlanModifierReplace, TagReplaceIfSet, TagReplaceIfConfigured, TagReplaceDecrease, TagReplaceFunc, a.value, a.pos+1))
+	return nil
 }
 
-func validators(tagV, attrType string, fromSlice bool, tags string) []tfsdk.AttributeValidator {
+func validators(calls []tagCall, attrType string, t attr.Type, fromSlice bool, tags, fieldPath string, diags *diag.Diagnostics) []tfsdk.AttributeValidator {
 	vals := []tfsdk.AttributeValidator{}
 
-	if hasTagArg(TagValidatorBetween, tagV) {
-		if v := betweenValidator(tagV, attrType, tags); v != nil {
+	if c, ok := findCall(calls, TagValidatorBetween); ok {
+		if v := betweenValidator(c, attrType, tags, fieldPath, diags); v != nil {
 			vals = append(vals, v)
 		}
 	}
 
-	// magic defaults and shortcuts (required = size > 0, optional = size >= 0)
-	if !hasTagArg(TagValidatorBetween, tagV) && attrType == SpecialTypeBlock { // between takes precedence
+	// magic defaults and shortcuts (required = size > 0, optional = size >= 0),
+	// not needed for single blocks
+	if !hasCall(calls, TagValidatorBetween) && attrType == SpecialTypeBlock { // between takes precedence
 		// fromSlice	Set		Required	Optional
 		// F			F						=> list.between(0,1)
 		// F			F					T	=> list.between(0,1)
@@ -481,240 +1519,446 @@ func validators(tagV, attrType string, fromSlice bool, tags string) []tfsdk.Attr
 		}
 	}
 
-	if hasTagArg(TagValidatorOneOf, tagV) {
-		if v := oneOfValidator(tagV, attrType, tags); v != nil {
+	if c, ok := findCall(calls, TagValidatorOneOf); ok {
+		if v := oneOfValidator(c, attrType, tags, fieldPath, diags); v != nil {
+			vals = append(vals, v)
+		}
+	}
+
+	if c, ok := findCall(calls, TagValidatorNoneOf); ok {
+		if v := noneOfValidator(c, attrType, tags, fieldPath, diags); v != nil {
 			vals = append(vals, v)
 		}
 	}
 
-	if hasTagArg(TagValidatorNoneOf, tagV) {
-		if v := noneOfValidator(tagV, attrType, tags); v != nil {
+	if c, ok := findCall(calls, TagValidatorRegex); ok {
+		if v := regexValidator(c, attrType, fieldPath, diags); v != nil {
 			vals = append(vals, v)
 		}
 	}
 
+	for _, name := range []string{TagValidatorLenAtLeast, TagValidatorLenAtMost} {
+		if c, ok := findCall(calls, name); ok {
+			if v := lengthValidator(c, attrType, fieldPath, diags); v != nil {
+				vals = append(vals, v)
+			}
+		}
+	}
+
+	for _, name := range []string{TagValidatorPrefix, TagValidatorSuffix, TagValidatorContains} {
+		if c, ok := findCall(calls, name); ok {
+			if v := stringCheck(c, attrType, fieldPath, diags); v != nil {
+				vals = append(vals, v)
+			}
+		}
+	}
+
+	for _, name := range []string{TagValidatorDuration, TagValidatorRFC3339, TagValidatorBase64} {
+		if c, ok := findCall(calls, name); ok {
+			if v := formatCheck(c, attrType, fieldPath, diags); v != nil {
+				vals = append(vals, v)
+			}
+		}
+	}
+
+	for _, name := range []string{TagValidatorEach, TagValidatorKeys} {
+		if c, ok := findCall(calls, name); ok {
+			if v := elementValidator(c, attrType, t, tags, fieldPath, diags); v != nil {
+				vals = append(vals, v)
+			}
+		}
+	}
+
+	vals = append(vals, registeredValidators(calls, t, fieldPath, diags)...)
+
 	if len(vals) > 0 {
 		return vals
 	}
 	return nil
 }
 
-func betweenValidator(betweenValue, attrType, tags string) tfsdk.AttributeValidator {
-	ta := tagArgs(TagValidatorBetween, betweenValue)
-	args := strings.Split(ta, ",")
-	if len(args) != 2 {
-		panic(fmt.Sprintf("%s requires 2 numeric args, got %d", TagValidatorBetween, len(args)))
+// elementValidator handles each(...), which applies validators to each
+// element of a list, set or map, and keys(...), which applies them to each
+// map key, eg, each(oneof(a,b),lenatmost(8)).
+func elementValidator(c tagCall, attrType string, t attr.Type, tags, fieldPath string, diags *diag.Diagnostics) tfsdk.AttributeValidator {
+	inner := []tagCall{}
+	for _, a := range c.args {
+		if a.call == nil && a.key == "" && isFormatValidator(a.value) {
+			// formats take no arguments so need no parentheses, eg, each(duration)
+			inner = append(inner, tagCall{name: a.value, pos: a.pos})
+			continue
+		}
+
+		if a.call == nil || a.key != "" {
+			addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s takes validators, eg, %s(oneof(a,b)), not %s, at column %d", c.name, c.name, a.value, a.pos+1))
+			return nil
+		}
+		inner = append(inner, *a.call)
+	}
+
+	et := collectionElemType(t)
+	if c.name == TagValidatorKeys {
+		et = types.StringType
+	}
+
+	if et == nil || (c.name == TagValidatorKeys && attrType != "types.MapType") {
+		kinds := "lists, sets and maps of values"
+		if c.name == TagValidatorKeys {
+			kinds = "maps of values"
+		}
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s is only for %s at column %d", c.name, kinds, c.pos+1))
+		return nil
+	}
+
+	errs := diags.ErrorsCount()
+	vals := validators(inner, baseType(et), et, false, tags, fieldPath, diags)
+	if diags.ErrorsCount() > errs {
+		return nil
+	}
+
+	if len(vals) == 0 {
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires validators for %s elements at column %d", c.name, baseType(et), c.pos+1))
+		return nil
+	}
+
+	switch {
+	case c.name == TagValidatorKeys:
+		return mapvalidator.KeysAre(vals...)
+	case attrType == "types.MapType":
+		return mapvalidator.ValuesAre(vals...)
+	case attrType == "types.SetType":
+		return setvalidator.ValuesAre(vals...)
+	}
+
+	return listvalidator.ValuesAre(vals...)
+}
+
+func betweenValidator(c tagCall, attrType, tags, fieldPath string, diags *diag.Diagnostics) tfsdk.AttributeValidator {
+	if len(c.args) != 2 {
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires 2 numeric args, got %d", TagValidatorBetween, len(c.args)))
+		return nil
 	}
 
 	nums := []float64{}
-	for _, a := range args {
-		n, err := strconv.ParseFloat(a, 64)
+	for _, a := range c.args {
+		n, err := strconv.ParseFloat(a.value, 64)
 		if err != nil {
-			panic(fmt.Sprintf("%s requires 2 numeric args: %s", TagValidatorBetween, err))
+			addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires 2 numeric args: %s at column %d", TagValidatorBetween, err, a.pos+1))
+			return nil
 		}
 		nums = append(nums, n)
 	}
 
 	switch attrType {
-	case "[]types.Bool", "[]bool",
-		"[]types.Float64", "[]float", "[]float64",
-		"[]types.Int64", "[]int64", "[]int",
-		"[]types.Number",
-		"[]types.String", "[]string", SpecialTypeBlock:
+	case SpecialTypeBlock:
 		if tagValue(TagCollection, tags) == TagCollectionSet {
 			return setvalidator.SizeBetween(int(nums[0]), int(nums[1]))
 		}
 		return listvalidator.SizeBetween(int(nums[0]), int(nums[1]))
-	case "types.ListType":
+	case "types.ListType", SpecialTypeListNested:
 		return listvalidator.SizeBetween(int(nums[0]), int(nums[1]))
-	case "types.SetType":
+	case "types.SetType", SpecialTypeSetNested:
 		return setvalidator.SizeBetween(int(nums[0]), int(nums[1]))
-	case "types.String", "string":
+	case "types.MapType", SpecialTypeMapNested:
+		return mapvalidator.SizeBetween(int(nums[0]), int(nums[1]))
+	case "types.String":
 		return stringvalidator.LengthBetween(int(nums[0]), int(nums[1]))
-	case "types.Float64", "float", "float64", "types.Number":
+	case "types.Float64", "types.Number":
 		return float64validator.Between(nums[0], nums[1])
-	case "types.Int64", "int", "int64":
+	case "types.Int64":
+		// large ints lose precision as floats
+		min, minErr := strconv.ParseInt(c.args[0].value, 10, 64)
+		max, maxErr := strconv.ParseInt(c.args[1].value, 10, 64)
+		if minErr == nil && maxErr == nil {
+			return int64validator.Between(min, max)
+		}
 		return int64validator.Between(int64(nums[0]), int64(nums[1]))
 	}
 
+	addUnsupportedError(diags, fieldPath, c, attrType)
 	return nil
 }
 
-func oneOfValidator(oneOfValue, attrType, tags string) tfsdk.AttributeValidator {
-	ta := tagArgs(TagValidatorOneOf, oneOfValue)
-	args := strings.Split(ta, ",")
+// addUnsupportedError reports a validator call on a type it does not support.
+func addUnsupportedError(diags *diag.Diagnostics, fieldPath string, c tagCall, attrType string) {
+	desc := attrType
+	switch attrType {
+	case SpecialTypeBlock:
+		desc = "blocks"
+	case SpecialTypeSingleBlock:
+		desc = "single blocks"
+	case SpecialTypeListNested, SpecialTypeSetNested, SpecialTypeMapNested, SpecialTypeSingleNested:
+		desc = "nested attributes"
+	}
+
+	addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s is not supported on %s at column %d", c.name, desc, c.pos+1))
+}
 
+func oneOfValidator(c tagCall, attrType, tags, fieldPath string, diags *diag.Diagnostics) tfsdk.AttributeValidator {
 	switch attrType {
-	case "types.Float64", "float", "float64":
+	case "types.Float64":
 		nums := []float64{}
-		for _, a := range args {
-			n, err := strconv.ParseFloat(a, 64)
+		for _, a := range c.args {
+			n, err := strconv.ParseFloat(a.value, 64)
 			if err != nil {
-				panic(fmt.Sprintf("%s requires numeric args: %s", TagValidatorOneOf, err))
+				addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires numeric args: %s at column %d", TagValidatorOneOf, err, a.pos+1))
+				return nil
 			}
 			nums = append(nums, n)
 		}
 		return float64validator.OneOf(nums...)
-	case "types.Int64", "int64", "int":
+	case "types.Int64":
 		nums := []int64{}
-		for _, a := range args {
-			n, err := strconv.ParseInt(a, 10, 64)
+		for _, a := range c.args {
+			n, err := strconv.ParseInt(a.value, 10, 64)
 			if err != nil {
-				panic(fmt.Sprintf("%s requires numeric args: %s", TagValidatorOneOf, err))
+				addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires numeric args: %s at column %d", TagValidatorOneOf, err, a.pos+1))
+				return nil
 			}
 			nums = append(nums, n)
 		}
 		return int64validator.OneOf(nums...)
 	case "types.Number":
 		nums := []*big.Float{}
-		for _, a := range args {
+		for _, a := range c.args {
 			bf := big.NewFloat(0.0)
-			bf, _, err := bf.Parse(a, 10)
+			bf, _, err := bf.Parse(a.value, 10)
 			if err != nil {
-				panic(fmt.Sprintf("%s requires numeric args: %s", TagValidatorOneOf, err))
+				addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires numeric args: %s at column %d", TagValidatorOneOf, err, a.pos+1))
+				return nil
 			}
 			nums = append(nums, bf)
 		}
 		return numbervalidator.OneOf(nums...)
-	case "types.String", "string":
-		return stringvalidator.OneOf(args...)
+	case "types.String":
+		return stringvalidator.OneOf(c.values()...)
 	}
 
+	addUnsupportedError(diags, fieldPath, c, attrType)
 	return nil
 }
 
-func noneOfValidator(noneOfValue, attrType, tags string) tfsdk.AttributeValidator {
-	ta := tagArgs(TagValidatorNoneOf, noneOfValue)
-	args := strings.Split(ta, ",")
-
+func noneOfValidator(c tagCall, attrType, tags, fieldPath string, diags *diag.Diagnostics) tfsdk.AttributeValidator {
 	switch attrType {
-	case "types.Float64", "float", "float64":
+	case "types.Float64":
 		nums := []float64{}
-		for _, a := range args {
-			n, err := strconv.ParseFloat(a, 64)
+		for _, a := range c.args {
+			n, err := strconv.ParseFloat(a.value, 64)
 			if err != nil {
-				panic(fmt.Sprintf("%s requires numeric args: %s", TagValidatorNoneOf, err))
+				addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires numeric args: %s at column %d", TagValidatorNoneOf, err, a.pos+1))
+				return nil
 			}
 			nums = append(nums, n)
 		}
 		return float64validator.NoneOf(nums...)
-	case "types.Int64", "int64", "int":
+	case "types.Int64":
 		nums := []int64{}
-		for _, a := range args {
-			n, err := strconv.ParseInt(a, 10, 64)
+		for _, a := range c.args {
+			n, err := strconv.ParseInt(a.value, 10, 64)
 			if err != nil {
-				panic(fmt.Sprintf("%s requires numeric args: %s", TagValidatorNoneOf, err))
+				addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires numeric args: %s at column %d", TagValidatorNoneOf, err, a.pos+1))
+				return nil
 			}
 			nums = append(nums, n)
 		}
 		return int64validator.NoneOf(nums...)
 	case "types.Number":
 		nums := []*big.Float{}
-		for _, a := range args {
+		for _, a := range c.args {
 			bf := big.NewFloat(0.0)
-			bf, _, err := bf.Parse(a, 10)
+			bf, _, err := bf.Parse(a.value, 10)
 			if err != nil {
-				panic(fmt.Sprintf("%s requires numeric args: %s", TagValidatorNoneOf, err))
+				addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires numeric args: %s at column %d", TagValidatorNoneOf, err, a.pos+1))
+				return nil
 			}
 			nums = append(nums, bf)
 		}
 		return numbervalidator.NoneOf(nums...)
-	case "types.String", "string":
-		return stringvalidator.NoneOf(args...)
+	case "types.String":
+		return stringvalidator.NoneOf(c.values()...)
 	}
 
+	addUnsupportedError(diags, fieldPath, c, attrType)
 	return nil
 }
 
-func tagValue(key string, allTags string) string {
-	tags := splitTags(allTags)
+// regexValidator handles regex(pattern) and regex(pattern,message=...), where
+// the message replaces the default error. Patterns with commas must be
+// quoted.
+func regexValidator(c tagCall, attrType, fieldPath string, diags *diag.Diagnostics) tfsdk.AttributeValidator {
+	if !stringValidatorType(c, attrType, fieldPath, diags) {
+		return nil
+	}
 
-	for _, tag := range tags {
-		parts := strings.Split(tag, ":")
-		if len(parts) != 2 {
-			continue
-		}
+	if len(c.args) < 1 || c.args[0].key != "" {
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires a pattern at column %d", TagValidatorRegex, c.pos+1))
+		return nil
+	}
 
-		if parts[0] == key {
-			return strings.TrimPrefix(strings.TrimSuffix(parts[1], "\""), "\"")
+	message := ""
+	for _, a := range c.args[1:] {
+		if a.key != "message" {
+			addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s takes a pattern, quoted if it has commas, and message=... at column %d", TagValidatorRegex, a.pos+1))
+			return nil
 		}
+		message = a.value
 	}
 
-	return ""
+	re, err := regexp.Compile(c.args[0].value)
+	if err != nil {
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s pattern does not compile: %s at column %d", TagValidatorRegex, err, c.args[0].pos+1))
+		return nil
+	}
+
+	return stringvalidator.RegexMatches(re, message)
+}
+
+// lengthValidator handles lenatleast(n) and lenatmost(n).
+func lengthValidator(c tagCall, attrType, fieldPath string, diags *diag.Diagnostics) tfsdk.AttributeValidator {
+	if !stringValidatorType(c, attrType, fieldPath, diags) {
+		return nil
+	}
+
+	if len(c.args) != 1 {
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires 1 numeric arg, got %d", c.name, len(c.args)))
+		return nil
+	}
+
+	n, err := strconv.Atoi(c.args[0].value)
+	if err != nil || n < 0 {
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires a length, not %s, at column %d", c.name, c.args[0].value, c.args[0].pos+1))
+		return nil
+	}
+
+	if c.name == TagValidatorLenAtLeast {
+		return stringvalidator.LengthAtLeast(n)
+	}
+
+	return stringvalidator.LengthAtMost(n)
 }
 
-func hasTagArg(needle, haystack string) bool {
-	h := splitTagValues(haystack)
+// stringCheck handles prefix(...), suffix(...) and contains(...).
+func stringCheck(c tagCall, attrType, fieldPath string, diags *diag.Diagnostics) tfsdk.AttributeValidator {
+	if !stringValidatorType(c, attrType, fieldPath, diags) {
+		return nil
+	}
 
-	for _, check := range h {
-		if check == needle || strings.HasPrefix(check, fmt.Sprintf("%s(", needle)) {
-			return true
-		}
+	if len(c.args) != 1 || c.args[0].key != "" {
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s requires 1 value, quoted if it has commas or parentheses, at column %d", c.name, c.pos+1))
+		return nil
 	}
 
-	return false
+	switch c.name {
+	case TagValidatorPrefix:
+		return StringHasPrefix(c.args[0].value)
+	case TagValidatorSuffix:
+		return StringHasSuffix(c.args[0].value)
+	}
+
+	return StringContains(c.args[0].value)
 }
 
-func tagArgs(needle, haystack string) string {
-	h := splitTagValues(haystack)
+// isFormatValidator reports whether name is duration, rfc3339 or base64.
+func isFormatValidator(name string) bool {
+	return name == TagValidatorDuration || name == TagValidatorRFC3339 || name == TagValidatorBase64
+}
 
-	for _, check := range h {
-		if strings.HasPrefix(check, fmt.Sprintf("%s(", needle)) {
-			return strings.TrimPrefix(strings.TrimSuffix(check, ")"), fmt.Sprintf("%s(", needle))
-		}
+// formatCheck handles duration, rfc3339 and base64.
+func formatCheck(c tagCall, attrType, fieldPath string, diags *diag.Diagnostics) tfsdk.AttributeValidator {
+	if !stringValidatorType(c, attrType, fieldPath, diags) {
+		return nil
 	}
 
-	return ""
+	if c.hasArgs {
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s takes no arguments at column %d", c.name, c.pos+1))
+		return nil
+	}
+
+	return formatValidator{Format: c.name}
 }
 
-func splitTagValues(s string) []string {
-	re := regexp.MustCompile(`(\([^\)]*),([^\)]*\))`)
+// stringValidatorType reports whether a string-only validator is on a string
+// attribute.
+func stringValidatorType(c tagCall, attrType, fieldPath string, diags *diag.Diagnostics) bool {
+	if attrType != "types.String" {
+		addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s is only for string attributes at column %d", c.name, c.pos+1))
+		return false
+	}
+
+	return true
+}
 
-	// extra juggling due to go's lack of lookahead in regex
-	result := re.ReplaceAllString(s, "$1|||||$2")
+// collectionElemType returns the element type of a list, set or map type, or
+// nil for other types.
+func collectionElemType(t attr.Type) attr.Type {
+	switch tt := t.(type) {
+	case types.ListType:
+		return tt.ElemType
+	case types.MapType:
+		return tt.ElemType
+	case types.SetType:
+		return tt.ElemType
+	case nil:
+		return nil
+	}
 
-	for true {
-		newResult := re.ReplaceAllString(result, "$1|||||$2")
-		if newResult != result {
-			result = newResult
-		} else {
-			break
-		}
+	// custom collection types go by their Terraform type
+	var et attr.Type
+	switch tt := t.TerraformType(context.Background()).(type) {
+	case tftypes.List:
+		et, _ = frameworkTypeOf(tt.ElementType)
+	case tftypes.Map:
+		et, _ = frameworkTypeOf(tt.ElementType)
+	case tftypes.Set:
+		et, _ = frameworkTypeOf(tt.ElementType)
 	}
 
-	p := []string{}
+	return et
+}
 
-	h := strings.Split(result, ",")
-	for _, v := range h {
-		p = append(p, strings.Replace(v, "|||||", ",", -1))
+// joinFieldPath appends a Go struct field name to a field path, such as
+// Endpoint.Field, used to locate problems in the model.
+func joinFieldPath(fieldPath, name string) string {
+	if fieldPath == "" {
+		return name
 	}
 
-	return p
+	return fmt.Sprintf("%s.%s", fieldPath, name)
 }
 
-func splitTags(s string) []string {
-	re := regexp.MustCompile(`(:"[^"]*) ([^"]*")`)
+// addFieldError records a problem with the field at fieldPath.
+func addFieldError(diags *diag.Diagnostics, fieldPath, detail string) {
+	diags.AddError(fmt.Sprintf("Invalid field %s", fieldPath), detail)
+}
 
-	// extra juggling due to go's lack of lookahead in regex
-	result := re.ReplaceAllString(s, "$1|||||$2")
+// addTagError records a problem with the tag of the field at fieldPath.
+func addTagError(diags *diag.Diagnostics, fieldPath, tag, detail string) {
+	diags.AddError(fmt.Sprintf("Invalid %s tag on field %s", tag, fieldPath), detail)
+}
 
-	for true {
-		newResult := re.ReplaceAllString(result, "$1|||||$2")
-		if newResult != result {
-			result = newResult
-		} else {
-			break
-		}
+// attrName returns the schema name for a field, which is the tfsdk tag when
+// present, so the schema matches what the framework expects when getting the
+// model, or else the snake case name. An empty name means the field is
+// skipped.
+func attrName(camel, allTags, fieldPath string, diags *diag.Diagnostics) string {
+	tfsdkName := tagValue(TagTfsdk, allTags)
+
+	if tfsdkName == TagTfsdkSkip {
+		diags.AddWarning(fmt.Sprintf("Skipped field %s", fieldPath), fmt.Sprintf(`field has %s:"%s" tag and is not in the schema`, TagTfsdk, TagTfsdkSkip))
+		return ""
 	}
 
-	p := []string{}
+	snakeName := tagValue(TagSnakeName, allTags)
+
+	if tfsdkName != "" && snakeName != "" && tfsdkName != snakeName {
+		addTagError(diags, fieldPath, TagSnakeName, fmt.Sprintf("snake name (%s) conflicts with tfsdk name (%s)", snakeName, tfsdkName))
+	}
 
-	h := strings.Split(result, " ")
-	for _, v := range h {
-		p = append(p, strings.Replace(v, "|||||", " ", -1))
+	if tfsdkName != "" {
+		return tfsdkName
 	}
 
-	return p
+	return snakeCase(camel, allTags)
 }
 
 func snakeCase(camel string, allTags string) string {
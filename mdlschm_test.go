@@ -1,22 +1,57 @@
 package mdlschm
 
 import (
+	"context"
+	"math"
 	"math/big"
 	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/numbervalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/schemavalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
+// testCommonFields, testTagsFields and TestRegionalFields are model
+// fragments for embedding.
+type testCommonFields struct {
+	ID  types.String `tfsdk:"id" computed:"true" pmods:"usfu"`
+	ARN types.String `tfsdk:"arn" computed:"true"`
+}
+
+type testTagsFields struct {
+	Tags    map[string]string `tfsdk:"tags" optional:"true"`
+	TagsAll map[string]string `tfsdk:"tags_all" computed:"true"`
+}
+
+type TestRegionalFields struct {
+	testTagsFields
+	Region types.String `optional:"true" valid:"conflicts(Name)"`
+}
+
+type TestNetworkFields struct {
+	SubnetIDs []types.String `optional:"true" collection:"set"`
+}
+
+type testOtherFields struct {
+	ARN types.String `computed:"true"`
+}
+
 func TestNew(t *testing.T) {
 	t.Parallel()
 
@@ -702,116 +737,1397 @@ func TestNew(t *testing.T) {
 				},
 			},
 		},
-	}
-
-	for name, test := range tests {
-		name, test := name, test
-
-		t.Run(name, func(t *testing.T) {
-			t.Parallel()
-
-			got := New(test.model)
-
-			diff := deep.Equal(got, test.want)
-			if diff != nil {
-				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, test.want, diff)
-			}
-		})
-	}
-}
-
-func TestSplitTagValues(t *testing.T) {
-	t.Parallel()
-
-	tests := map[string]struct {
-		args string
-		want []string
-	}{
-		"Basic": {
-			args: `between(3,32)`,
-			want: []string{
-				"between(3,32)",
+		"TfsdkNames": {
+			model: struct {
+				VPCEndpointIDs []string     `tfsdk:"endpoint_ids"`
+				HTTPSPort      types.Int64  `tfsdk:"port" snake:"port"`
+				Internal       types.String `tfsdk:"-"`
+				DNSName        types.String
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"endpoint_ids": {
+						Type: types.ListType{
+							ElemType: types.StringType,
+						},
+						Optional: true,
+					},
+					"port": {
+						Type:     types.Int64Type,
+						Optional: true,
+					},
+					"dns_name": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+				},
 			},
 		},
-		"Test2": {
-			args: `between(3,32),arbitrary(5,2,3,1)`,
-			want: []string{
-				"between(3,32)",
-				"arbitrary(5,2,3,1)",
+		"QuotedTagArgs": {
+			model: struct {
+				Size  types.String `tfsdk:"size" valid:"oneof('small, medium',large)" pmods:"default('small, medium')"`
+				Query types.String `tfsdk:"query" valid:"noneof(\"a\\\"b\")" pmods:"default(f(x))"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"size": {
+						Type:     types.StringType,
+						Optional: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.String{Value: "small, medium"}),
+						},
+						Validators: []tfsdk.AttributeValidator{
+							stringvalidator.OneOf("small, medium", "large"),
+						},
+					},
+					"query": {
+						Type:     types.StringType,
+						Optional: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.String{Value: "f(x)"}),
+						},
+						Validators: []tfsdk.AttributeValidator{
+							stringvalidator.NoneOf(`a"b`),
+						},
+					},
+				},
 			},
 		},
-		"Test3": {
-			args: `fred,any(5),toast(1,8),between(3,32),arbitrary(5,2,3,1)`,
-			want: []string{
-				"fred",
-				"any(5)",
-				"toast(1,8)",
-				"between(3,32)",
-				"arbitrary(5,2,3,1)",
+		"ScalarDefaults": {
+			model: struct {
+				Count  int            `computed:"true" pmods:"default(3)"`
+				Size   int64          `computed:"true" pmods:"default(4)"`
+				Ratio  float64        `computed:"true" pmods:"default(0.5)"`
+				Amount types.Number   `computed:"true" pmods:"default(1.5)"`
+				Ready  bool           `computed:"true" pmods:"default(true)"`
+				Limits map[string]int `computed:"true" pmods:"default({cpu=2})"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"count": {
+						Type:     types.Int64Type,
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.Int64{Value: 3}),
+						},
+					},
+					"size": {
+						Type:     types.Int64Type,
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.Int64{Value: 4}),
+						},
+					},
+					"ratio": {
+						Type:     types.Float64Type,
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.Float64{Value: 0.5}),
+						},
+					},
+					"amount": {
+						Type:     types.NumberType,
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.Number{Value: big.NewFloat(1.5)}),
+						},
+					},
+					"ready": {
+						Type:     types.BoolType,
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.Bool{Value: true}),
+						},
+					},
+					"limits": {
+						Type: types.MapType{
+							ElemType: types.Int64Type,
+						},
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.Map{
+								ElemType: types.Int64Type,
+								Elems:    map[string]attr.Value{"cpu": types.Int64{Value: 2}},
+							}),
+						},
+					},
+				},
 			},
 		},
-	}
-
-	for name, test := range tests {
-		name, test := name, test
-
-		t.Run(name, func(t *testing.T) {
-			t.Parallel()
-
-			got := splitTagValues(test.args)
-
-			if !reflect.DeepEqual(got, test.want) {
-				t.Errorf("unexpected difference:\ngot %+v\nexpected %+v", got, test.want)
-			}
-		})
-	}
-}
-
-func TestSplitTags(t *testing.T) {
-	t.Parallel()
-
-	tests := map[string]struct {
-		tags string
-		want []string
-	}{
-		"Basic": {
-			tags: `tfsdk:"name" required:"true" valid:"between(3,32)"`,
-			want: []string{
-				`tfsdk:"name"`,
-				`required:"true"`,
-				`valid:"between(3,32)"`,
+		"CollectionDefaults": {
+			model: struct {
+				Names  []types.String          `computed:"true" pmods:"default([a,'b, c'])"`
+				Ports  []types.Int64           `computed:"true" collection:"set" pmods:"default([])"`
+				Labels map[string]types.String `computed:"true" pmods:"default({env=dev,'team name'=core})"`
+				Matrix types.List              `computed:"true" elem:"list(int64)" pmods:"default([[1,2],[3]])"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"names": {
+						Type: types.ListType{
+							ElemType: types.StringType,
+						},
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.List{
+								ElemType: types.StringType,
+								Elems:    []attr.Value{types.String{Value: "a"}, types.String{Value: "b, c"}},
+							}),
+						},
+					},
+					"ports": {
+						Type: types.SetType{
+							ElemType: types.Int64Type,
+						},
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.Set{
+								ElemType: types.Int64Type,
+								Elems:    []attr.Value{},
+							}),
+						},
+					},
+					"labels": {
+						Type: types.MapType{
+							ElemType: types.StringType,
+						},
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.Map{
+								ElemType: types.StringType,
+								Elems: map[string]attr.Value{
+									"env":       types.String{Value: "dev"},
+									"team name": types.String{Value: "core"},
+								},
+							}),
+						},
+					},
+					"matrix": {
+						Type: types.ListType{
+							ElemType: types.ListType{
+								ElemType: types.Int64Type,
+							},
+						},
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							DefaultValue(types.List{
+								ElemType: types.ListType{ElemType: types.Int64Type},
+								Elems: []attr.Value{
+									types.List{ElemType: types.Int64Type, Elems: []attr.Value{types.Int64{Value: 1}, types.Int64{Value: 2}}},
+									types.List{ElemType: types.Int64Type, Elems: []attr.Value{types.Int64{Value: 3}}},
+								},
+							}),
+						},
+					},
+				},
 			},
 		},
-		"Test2": {
-			tags: `tfsdk:"name" required:"true" valid:"between(3, 32)"`,
-			want: []string{
-				`tfsdk:"name"`,
-				`required:"true"`,
-				`valid:"between(3, 32)"`,
+		"StringValidators": {
+			model: struct {
+				Name  types.String `tfsdk:"name" valid:"regex('^[a-z]{1,8}$',message='must be 1-8 lowercase letters'),lenatleast(1)"`
+				Code  types.String `tfsdk:"code" valid:"regex(^[A-Z]+$),lenatmost(5)"`
+				ARN   types.String `tfsdk:"arn" valid:"prefix('arn:'),contains(':iam:')"`
+				Email types.String `tfsdk:"email" valid:"suffix(@example.com)"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"name": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z]{1,8}$`), "must be 1-8 lowercase letters"),
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"code": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Z]+$`), ""),
+							stringvalidator.LengthAtMost(5),
+						},
+					},
+					"arn": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							StringHasPrefix("arn:"),
+							StringContains(":iam:"),
+						},
+					},
+					"email": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							StringHasSuffix("@example.com"),
+						},
+					},
+				},
 			},
 		},
-		"Test3": {
-			tags: `tfsdk:"name" md:"This is a description with spaces" valid:"between( 3, 32 )"`,
-			want: []string{
-				`tfsdk:"name"`,
-				`md:"This is a description with spaces"`,
-				`valid:"between( 3, 32 )"`,
+		"CrossValidators": {
+			model: struct {
+				SubnetID   types.String `valid:"exactlyoneof(SubnetName)"`
+				SubnetName types.String `valid:"exactlyoneof(subnet_id),conflicts(port)"`
+				Port       types.Int64  `valid:"alsorequires(Protocol)"`
+				Protocol   types.String
+				Rules      []struct {
+					CIDR       types.String `tfsdk:"cidr" valid:"atleastoneof(PrefixList)"`
+					PrefixList types.String
+				}
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"subnet_id": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							schemavalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("subnet_name")),
+						},
+					},
+					"subnet_name": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							schemavalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("subnet_id")),
+							schemavalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("port")),
+						},
+					},
+					"port": {
+						Type:     types.Int64Type,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							schemavalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("protocol")),
+						},
+					},
+					"protocol": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+				},
+				Blocks: map[string]tfsdk.Block{
+					"rules": {
+						NestingMode: tfsdk.BlockNestingModeList,
+						Attributes: map[string]tfsdk.Attribute{
+							"cidr": {
+								Type:     types.StringType,
+								Optional: true,
+								Validators: []tfsdk.AttributeValidator{
+									schemavalidator.AtLeastOneOf(path.MatchRelative().AtParent().AtName("prefix_list")),
+								},
+							},
+							"prefix_list": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+						},
+					},
+				},
 			},
 		},
-	}
-
-	for name, test := range tests {
-		name, test := name, test
-
-		t.Run(name, func(t *testing.T) {
-			t.Parallel()
-
-			got := splitTags(test.tags)
-
-			if !reflect.DeepEqual(got, test.want) {
-				t.Errorf("unexpected difference:\ngot %+v\nexpected %+v", got, test.want)
-			}
-		})
+		"ElementValidators": {
+			model: struct {
+				Names   []types.String         `valid:"each(oneof(a,b),lenatmost(8))"`
+				Ports   []types.Int64          `collection:"set" valid:"between(1,3),each(between(1,1024))"`
+				Weights map[string]types.Int64 `valid:"each(between(1,3)),keys(regex(^[a-z]+$))"`
+				Aliases types.List             `elem:"string" valid:"each(prefix(w-))"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"names": {
+						Type: types.ListType{
+							ElemType: types.StringType,
+						},
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							listvalidator.ValuesAre(stringvalidator.OneOf("a", "b"), stringvalidator.LengthAtMost(8)),
+						},
+					},
+					"ports": {
+						Type: types.SetType{
+							ElemType: types.Int64Type,
+						},
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							setvalidator.SizeBetween(1, 3),
+							setvalidator.ValuesAre(int64validator.Between(1, 1024)),
+						},
+					},
+					"weights": {
+						Type: types.MapType{
+							ElemType: types.Int64Type,
+						},
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							mapvalidator.ValuesAre(int64validator.Between(1, 3)),
+							mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z]+$`), "")),
+						},
+					},
+					"aliases": {
+						Type: types.ListType{
+							ElemType: types.StringType,
+						},
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							listvalidator.ValuesAre(StringHasPrefix("w-")),
+						},
+					},
+				},
+			},
+		},
+		"FrameworkCollections": {
+			model: struct {
+				Aliases   types.List   `required:"true" elem:"string" valid:"between(1,5)"`
+				Ports     types.Set    `elem:"int64" valid:"between(0,10)"`
+				Labels    types.Map    `computed:"true" elem:"string" valid:"between(0,3)"`
+				Matrix    types.List   `elem:"list(float64)"`
+				Endpoints types.List   `elem:"object(endpoint)"`
+				Primary   types.Object `elem:"object(endpoint)"`
+				endpoint  struct {
+					URL   types.String
+					Port  int64
+					Tags  map[string]string
+					Paths types.Set `elem:"string"`
+				}
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"aliases": {
+						Type: types.ListType{
+							ElemType: types.StringType,
+						},
+						Required: true,
+						Validators: []tfsdk.AttributeValidator{
+							listvalidator.SizeBetween(1, 5),
+						},
+					},
+					"ports": {
+						Type: types.SetType{
+							ElemType: types.Int64Type,
+						},
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							setvalidator.SizeBetween(0, 10),
+						},
+					},
+					"labels": {
+						Type: types.MapType{
+							ElemType: types.StringType,
+						},
+						Computed: true,
+						Validators: []tfsdk.AttributeValidator{
+							mapvalidator.SizeBetween(0, 3),
+						},
+					},
+					"matrix": {
+						Type: types.ListType{
+							ElemType: types.ListType{
+								ElemType: types.Float64Type,
+							},
+						},
+						Optional: true,
+					},
+					"endpoints": {
+						Type: types.ListType{
+							ElemType: types.ObjectType{
+								AttrTypes: map[string]attr.Type{
+									"url":  types.StringType,
+									"port": types.Int64Type,
+									"tags": types.MapType{
+										ElemType: types.StringType,
+									},
+									"paths": types.SetType{
+										ElemType: types.StringType,
+									},
+								},
+							},
+						},
+						Optional: true,
+					},
+					"primary": {
+						Type: types.ObjectType{
+							AttrTypes: map[string]attr.Type{
+								"url":  types.StringType,
+								"port": types.Int64Type,
+								"tags": types.MapType{
+									ElemType: types.StringType,
+								},
+								"paths": types.SetType{
+									ElemType: types.StringType,
+								},
+							},
+						},
+						Optional: true,
+					},
+				},
+			},
+		},
+		"SingleBlocks": {
+			model: struct {
+				Endpoint struct {
+					URL types.String `required:"true"`
+				} `collection:"single" required:"true"`
+				Logging struct {
+					Level types.String `optional:"true"`
+				}
+			}{},
+			want: tfsdk.Schema{
+				Blocks: map[string]tfsdk.Block{
+					"endpoint": {
+						Attributes: map[string]tfsdk.Attribute{
+							"url": {
+								Type:     types.StringType,
+								Required: true,
+							},
+						},
+						NestingMode: tfsdk.BlockNestingModeSingle,
+					},
+					"logging": {
+						Attributes: map[string]tfsdk.Attribute{
+							"level": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+						},
+						NestingMode: tfsdk.BlockNestingModeList,
+						Validators: []tfsdk.AttributeValidator{
+							listvalidator.SizeBetween(0, 1),
+						},
+					},
+				},
+			},
+		},
+		"SingleBlocksModelWide": {
+			model: struct {
+				_        struct{} `collection:"single"`
+				Endpoint struct {
+					URL types.String `required:"true"`
+				}
+				Rules []struct {
+					Name types.String `required:"true"`
+				}
+			}{},
+			want: tfsdk.Schema{
+				Blocks: map[string]tfsdk.Block{
+					"endpoint": {
+						Attributes: map[string]tfsdk.Attribute{
+							"url": {
+								Type:     types.StringType,
+								Required: true,
+							},
+						},
+						NestingMode: tfsdk.BlockNestingModeSingle,
+					},
+					"rules": {
+						Attributes: map[string]tfsdk.Attribute{
+							"name": {
+								Type:     types.StringType,
+								Required: true,
+							},
+						},
+						NestingMode: tfsdk.BlockNestingModeList,
+					},
+				},
+			},
+		},
+		"NestedAttributes": {
+			model: struct {
+				Listeners []struct {
+					Port types.Int64 `required:"true"`
+				} `nesting:"attribute" required:"true" valid:"between(1,3)"`
+				Origins []struct {
+					Host types.String `optional:"true"`
+				} `nesting:"attribute" collection:"set"`
+				Endpoint struct {
+					URL    types.String `computed:"true"`
+					Health struct {
+						Path types.String `optional:"true"`
+					}
+				} `nesting:"attribute" computed:"true"`
+				Routes map[string]struct {
+					Weight types.Int64 `required:"true"`
+				} `optional:"true"`
+				Logging []struct {
+					Level types.String `optional:"true"`
+				}
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"listeners": {
+						Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+							"port": {
+								Type:     types.Int64Type,
+								Required: true,
+							},
+						}),
+						Required: true,
+						Validators: []tfsdk.AttributeValidator{
+							listvalidator.SizeBetween(1, 3),
+						},
+					},
+					"origins": {
+						Attributes: tfsdk.SetNestedAttributes(map[string]tfsdk.Attribute{
+							"host": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+						}),
+						Optional: true,
+					},
+					"endpoint": {
+						Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+							"url": {
+								Type:     types.StringType,
+								Computed: true,
+							},
+							"health": {
+								Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+									"path": {
+										Type:     types.StringType,
+										Optional: true,
+									},
+								}),
+								Optional: true,
+							},
+						}),
+						Computed: true,
+					},
+					"routes": {
+						Attributes: tfsdk.MapNestedAttributes(map[string]tfsdk.Attribute{
+							"weight": {
+								Type:     types.Int64Type,
+								Required: true,
+							},
+						}),
+						Optional: true,
+					},
+				},
+				Blocks: map[string]tfsdk.Block{
+					"logging": {
+						Attributes: map[string]tfsdk.Attribute{
+							"level": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+						},
+						NestingMode: tfsdk.BlockNestingModeList,
+					},
+				},
+			},
+		},
+		"NestedAttributesModelWide": {
+			model: struct {
+				_        struct{} `nesting:"attribute"`
+				Listener struct {
+					Port types.Int64 `required:"true"`
+				} `required:"true"`
+				Logging []struct {
+					Level types.String `optional:"true"`
+				} `nesting:"block"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"listener": {
+						Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+							"port": {
+								Type:     types.Int64Type,
+								Required: true,
+							},
+						}),
+						Required: true,
+					},
+				},
+				Blocks: map[string]tfsdk.Block{
+					"logging": {
+						Attributes: map[string]tfsdk.Attribute{
+							"level": {
+								Type:     types.StringType,
+								Optional: true,
+							},
+						},
+						NestingMode: tfsdk.BlockNestingModeList,
+					},
+				},
+			},
+		},
+		"Kinds": {
+			model: struct {
+				Name     *string           `required:"true"`
+				Size     *int64            `optional:"true"`
+				Weight   float32           `optional:"true"`
+				Port     uint16            `required:"true" valid:"between(1,1024)"`
+				Offset   int32             `optional:"true"`
+				Count    uint              `computed:"true"`
+				Aliases  []*string         `optional:"true"`
+				Levels   []int8            `optional:"true" collection:"set"`
+				Features map[string]*bool  `optional:"true"`
+				Limits   map[string]uint32 `optional:"true"`
+				Region   testRegion        `optional:"true"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"name": {
+						Type:     types.StringType,
+						Required: true,
+					},
+					"size": {
+						Type:     types.Int64Type,
+						Optional: true,
+					},
+					"weight": {
+						Type:     types.Float64Type,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							float64validator.Between(-math.MaxFloat32, math.MaxFloat32),
+						},
+					},
+					"port": {
+						Type:     types.Int64Type,
+						Required: true,
+						Validators: []tfsdk.AttributeValidator{
+							int64validator.Between(1, 1024),
+							int64validator.Between(0, 65535),
+						},
+					},
+					"offset": {
+						Type:     types.Int64Type,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							int64validator.Between(-2147483648, 2147483647),
+						},
+					},
+					"count": {
+						Type:     types.Int64Type,
+						Computed: true,
+						Validators: []tfsdk.AttributeValidator{
+							int64validator.Between(0, 9223372036854775807),
+						},
+					},
+					"aliases": {
+						Type:     types.ListType{ElemType: types.StringType},
+						Optional: true,
+					},
+					"levels": {
+						Type:     types.SetType{ElemType: types.Int64Type},
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							setvalidator.ValuesAre(int64validator.Between(-128, 127)),
+						},
+					},
+					"features": {
+						Type:     types.MapType{ElemType: types.BoolType},
+						Optional: true,
+					},
+					"limits": {
+						Type:     types.MapType{ElemType: types.Int64Type},
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							mapvalidator.ValuesAre(int64validator.Between(0, 4294967295)),
+						},
+					},
+					"region": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"Formats": {
+			model: struct {
+				Interval time.Duration   `required:"true"`
+				Expires  *time.Time      `optional:"true"`
+				Data     []byte          `optional:"true"`
+				Waits    []time.Duration `optional:"true"`
+				Token    types.String    `optional:"true" valid:"base64"`
+				Started  types.String    `computed:"true" valid:"rfc3339"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"interval": {
+						Type:     types.StringType,
+						Required: true,
+						Validators: []tfsdk.AttributeValidator{
+							StringIsDuration(),
+						},
+					},
+					"expires": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							StringIsRFC3339(),
+						},
+					},
+					"data": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							StringIsBase64(),
+						},
+					},
+					"waits": {
+						Type:     types.ListType{ElemType: types.StringType},
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							listvalidator.ValuesAre(StringIsDuration()),
+						},
+					},
+					"token": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							StringIsBase64(),
+						},
+					},
+					"started": {
+						Type:     types.StringType,
+						Computed: true,
+						Validators: []tfsdk.AttributeValidator{
+							StringIsRFC3339(),
+						},
+					},
+				},
+			},
+		},
+		"Embedded": {
+			model: struct {
+				testCommonFields
+				TestRegionalFields
+				Name types.String `required:"true"`
+			}{},
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"id": {
+						Type:     types.StringType,
+						Computed: true,
+						PlanModifiers: []tfsdk.AttributePlanModifier{
+							resource.UseStateForUnknown(),
+						},
+					},
+					"arn": {
+						Type:     types.StringType,
+						Computed: true,
+					},
+					"tags": {
+						Type:     types.MapType{ElemType: types.StringType},
+						Optional: true,
+					},
+					"tags_all": {
+						Type:     types.MapType{ElemType: types.StringType},
+						Computed: true,
+					},
+					"region": {
+						Type:     types.StringType,
+						Optional: true,
+						Validators: []tfsdk.AttributeValidator{
+							schemavalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("name")),
+						},
+					},
+					"name": {
+						Type:     types.StringType,
+						Required: true,
+					},
+				},
+			},
+		},
+		"EmbeddedNamed": {
+			model: struct {
+				TestNetworkFields `tfsdk:"network" collection:"single"`
+			}{},
+			want: tfsdk.Schema{
+				Blocks: map[string]tfsdk.Block{
+					"network": {
+						Attributes: map[string]tfsdk.Attribute{
+							"subnet_ids": {
+								Type:     types.SetType{ElemType: types.StringType},
+								Optional: true,
+							},
+						},
+						NestingMode: tfsdk.BlockNestingModeSingle,
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := New(test.model)
+
+			diff := deep.Equal(got, test.want)
+			if diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, test.want, diff)
+			}
+		})
+	}
+}
+
+func TestNewKindsGet(t *testing.T) {
+	t.Parallel()
+
+	type model struct {
+		Name    *string           `tfsdk:"name" optional:"true"`
+		Port    uint16            `tfsdk:"port" required:"true"`
+		Weight  float32           `tfsdk:"weight" optional:"true"`
+		Aliases []*string         `tfsdk:"aliases" optional:"true"`
+		Limits  map[string]uint32 `tfsdk:"limits" optional:"true"`
+	}
+
+	ctx := context.Background()
+	schm := New(model{})
+
+	raw := tftypes.NewValue(schm.Type().TerraformType(ctx), map[string]tftypes.Value{
+		"name":    tftypes.NewValue(tftypes.String, nil),
+		"port":    tftypes.NewValue(tftypes.Number, 443),
+		"weight":  tftypes.NewValue(tftypes.Number, 0.5),
+		"aliases": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "www")}),
+		"limits":  tftypes.NewValue(tftypes.Map{ElementType: tftypes.Number}, map[string]tftypes.Value{"cpu": tftypes.NewValue(tftypes.Number, 2)}),
+	})
+
+	var got model
+	if diags := (tfsdk.Plan{Schema: schm, Raw: raw}).Get(ctx, &got); diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	www := "www"
+	want := model{
+		Port:    443,
+		Weight:  0.5,
+		Aliases: []*string{&www},
+		Limits:  map[string]uint32{"cpu": 2},
+	}
+
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, want, diff)
+	}
+}
+
+func TestNewE(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		model any
+		want  []string
+	}{
+		"NoProblems": {
+			model: struct {
+				Name types.String `tfsdk:"name" required:"true"`
+			}{},
+		},
+		"NotStruct": {
+			model: "name",
+			want: []string{
+				"Invalid model",
+			},
+		},
+		"Version": {
+			model: struct {
+				_    struct{}     `version:"one"`
+				Name types.String `tfsdk:"name" required:"true"`
+			}{},
+			want: []string{
+				"Invalid version tag on field _",
+			},
+		},
+		"AllProblems": {
+			model: struct {
+				Name     types.String `tfsdk:"name" valid:"between(3)"`
+				Enabled  types.Bool   `tfsdk:"enabled" pmods:"default(nope)"`
+				Count    types.Int64  `tfsdk:"count" valid:"oneof(1,two)"`
+				Unknown  []complex64  `tfsdk:"unknown"`
+				Endpoint struct {
+					Port types.Int64 `tfsdk:"port" valid:"noneof(x)"`
+				} `tfsdk:"endpoint"`
+			}{},
+			want: []string{
+				"Invalid valid tag on field Name",
+				"Invalid pmods tag on field Enabled",
+				"Invalid valid tag on field Count",
+				"Invalid field Unknown",
+				"Invalid valid tag on field Endpoint.Port",
+			},
+		},
+		"BlockInNestedAttribute": {
+			model: struct {
+				Endpoint struct {
+					Health struct {
+						Path types.String `optional:"true"`
+					} `nesting:"block"`
+				} `nesting:"attribute"`
+			}{},
+			want: []string{
+				"Invalid nesting tag on field Endpoint",
+			},
+		},
+		"SingleSlice": {
+			model: struct {
+				Rules []struct {
+					Name types.String `required:"true"`
+				} `collection:"single"`
+			}{},
+			want: []string{
+				"Invalid collection tag on field Rules",
+			},
+		},
+		"SnakeConflict": {
+			model: struct {
+				HTTPSPort types.Int64 `tfsdk:"https_port" snake:"port"`
+			}{},
+			want: []string{
+				"Invalid snake tag on field HTTPSPort",
+			},
+		},
+		"Elem": {
+			model: struct {
+				Aliases   types.List   `tfsdk:"aliases"`
+				Ports     types.Set    `tfsdk:"ports" elem:"int32"`
+				Endpoints types.List   `tfsdk:"endpoints" elem:"object(missing)"`
+				Primary   types.Object `tfsdk:"primary" elem:"string"`
+			}{},
+			want: []string{
+				"Invalid elem tag on field Aliases",
+				"Invalid elem tag on field Ports",
+				"Invalid elem tag on field Endpoints",
+				"Invalid elem tag on field Primary",
+			},
+		},
+		"TagSyntax": {
+			model: struct {
+				Size    types.String `tfsdk:"size" valid:"oneof(small,'large)"`
+				Name    types.String `tfsdk:"name" pmods:"default(a,b)"`
+				Enabled types.Bool   `tfsdk:"enabled" pmods:"replace usfu"`
+			}{},
+			want: []string{
+				"Invalid valid tag on field Size",
+				"Invalid pmods tag on field Name",
+				"Invalid pmods tag on field Enabled",
+			},
+		},
+		"CollectionDefaults": {
+			model: struct {
+				Ports  []types.Int64           `collection:"set" pmods:"default([80,http])"`
+				Names  []types.String          `pmods:"default({a=b})"`
+				Labels map[string]types.String `pmods:"default({env})"`
+				Zones  []types.String          `collection:"set" pmods:"default([a,a])"`
+				Rule   []struct {
+					Port types.Int64 `tfsdk:"port"`
+				} `pmods:"default([{proto=tcp}])"`
+			}{},
+			want: []string{
+				"Invalid pmods tag on field Ports",
+				"Invalid pmods tag on field Names",
+				"Invalid pmods tag on field Labels",
+				"Invalid pmods tag on field Zones",
+				"Invalid pmods tag on field Rule",
+			},
+		},
+		"StringValidators": {
+			model: struct {
+				Name  types.String `tfsdk:"name" valid:"regex('^[a-z')"`
+				Code  types.String `tfsdk:"code" valid:"regex(^[A-Z]+,[0-9]+$)"`
+				Size  types.String `tfsdk:"size" valid:"lenatleast(x)"`
+				Count types.Int64  `tfsdk:"count" valid:"prefix(1)"`
+			}{},
+			want: []string{
+				"Invalid valid tag on field Name",
+				"Invalid valid tag on field Code",
+				"Invalid valid tag on field Size",
+				"Invalid valid tag on field Count",
+			},
+		},
+		"BetweenTypes": {
+			model: struct {
+				Enabled types.Bool   `valid:"between(0,1)"`
+				Flags   []types.Bool `valid:"each(between(0,1))"`
+				Logging struct {
+					Bucket types.String `tfsdk:"bucket"`
+				} `collection:"single" valid:"between(1,1)"`
+				Owner struct {
+					Name types.String `tfsdk:"name"`
+				} `nesting:"attribute" collection:"single" valid:"between(1,1)"`
+			}{},
+			want: []string{
+				"Invalid valid tag on field Enabled",
+				"Invalid valid tag on field Flags",
+				"Invalid valid tag on field Logging",
+				"Invalid valid tag on field Owner",
+			},
+		},
+		"OneOfTypes": {
+			model: struct {
+				Enabled types.Bool     `valid:"oneof(true)"`
+				Names   []types.String `valid:"oneof(a)"`
+				Flags   []types.Bool   `valid:"each(noneof(false))"`
+				Count   types.Int64    `valid:"noneof(1,two)"`
+				Ratio   types.Float64  `valid:"oneof(0.5,half)"`
+			}{},
+			want: []string{
+				"Invalid valid tag on field Enabled",
+				"Invalid valid tag on field Names",
+				"Invalid valid tag on field Flags",
+				"Invalid valid tag on field Count",
+				"Invalid valid tag on field Ratio",
+			},
+		},
+		"CrossValidators": {
+			model: struct {
+				SubnetID   types.String `valid:"exactlyoneof(subnet)"`
+				SubnetName types.String `valid:"conflicts(SubnetName)"`
+				Port       types.Int64  `valid:"alsorequires()"`
+				Rules      []struct {
+					CIDR types.String `valid:"atleastoneof(Port)"`
+				}
+			}{},
+			want: []string{
+				"Invalid valid tag on field Rules.CIDR",
+				"Invalid valid tag on field SubnetID",
+				"Invalid valid tag on field SubnetName",
+				"Invalid valid tag on field Port",
+			},
+		},
+		"ElementValidators": {
+			model: struct {
+				Names []types.String         `valid:"each(x)"`
+				Name  types.String           `valid:"each(oneof(a))"`
+				Ports []types.Int64          `valid:"keys(oneof(a))"`
+				Tags  map[string]types.Int64 `valid:"each(prefix(a))"`
+			}{},
+			want: []string{
+				"Invalid valid tag on field Names",
+				"Invalid valid tag on field Name",
+				"Invalid valid tag on field Ports",
+				"Invalid valid tag on field Tags",
+			},
+		},
+		"MapKeys": {
+			model: struct {
+				Routes map[int]struct {
+					Weight types.Int64 `required:"true"`
+				}
+			}{},
+			want: []string{
+				"Invalid field Routes",
+			},
+		},
+		"Collisions": {
+			model: struct {
+				testCommonFields
+				testOtherFields
+				ID types.String `computed:"true"`
+			}{},
+			want: []string{
+				"Invalid field testOtherFields.ARN",
+				"Invalid field ID",
+			},
+		},
+		"EmbeddedPointer": {
+			model: struct {
+				*TestNetworkFields
+				Name types.String `required:"true"`
+			}{},
+			want: []string{
+				"Invalid field TestNetworkFields",
+			},
+		},
+		"EmbeddedUnexportedPointer": {
+			model: struct {
+				*testCommonFields
+				Name types.String `required:"true"`
+			}{},
+			want: []string{
+				"Invalid field testCommonFields",
+			},
+		},
+		"DefaultFrom": {
+			model: struct {
+				Name   types.String `required:"true"`
+				Count  types.Int64  `optional:"true"`
+				Alias  types.String `optional:"true" computed:"true" pmods:"default(attr=Missing)"`
+				Self   types.String `optional:"true" computed:"true" pmods:"default(attr=Self)"`
+				Size   types.String `optional:"true" computed:"true" pmods:"default(attr=Count)"`
+				Region types.String `optional:"true" computed:"true" pmods:"default(var=AWS_REGION)"`
+			}{},
+			want: []string{
+				"Invalid pmods tag on field Region",
+				"Invalid pmods tag on field Alias",
+				"Invalid pmods tag on field Self",
+				"Invalid pmods tag on field Size",
+			},
+		},
+		"KindDefaults": {
+			model: struct {
+				Size   uint8            `computed:"true" pmods:"default(300)"`
+				Ports  []uint16         `computed:"true" pmods:"default([80,70000])"`
+				Offset int8             `computed:"true" pmods:"default(-128)"`
+				Limits map[string]int32 `computed:"true" pmods:"default({max=2147483648})"`
+				Count  uint             `computed:"true" pmods:"default(-1)"`
+				Weight float32          `computed:"true" pmods:"default(1e300)"`
+				Ratio  float32          `computed:"true" pmods:"default(0.5)"`
+			}{},
+			want: []string{
+				"Invalid pmods tag on field Size",
+				"Invalid pmods tag on field Ports",
+				"Invalid pmods tag on field Limits",
+				"Invalid pmods tag on field Count",
+				"Invalid pmods tag on field Weight",
+			},
+		},
+		"BlockDefaults": {
+			model: struct {
+				Rules []struct {
+					Port types.Int64 `tfsdk:"port"`
+				} `pmods:"default([{port=80}])"`
+				Ingress []struct {
+					Port types.Int64 `tfsdk:"port"`
+				} `pmods:"default(attr=Rules)"`
+				Egress []struct {
+					Port types.Int64 `tfsdk:"port"`
+				} `pmods:"default(env=EGRESS)"`
+				Logging struct {
+					Bucket types.String `tfsdk:"bucket"`
+				} `collection:"single" pmods:"default({bucket=logs})"`
+			}{},
+			want: []string{
+				"Invalid pmods tag on field Rules",
+				"Invalid pmods tag on field Ingress",
+				"Invalid pmods tag on field Egress",
+				"Invalid pmods tag on field Logging",
+			},
+		},
+		"Interfaces": {
+			model: struct {
+				Name  types.String `required:"true"`
+				Value attr.Value   `optional:"true"`
+				Any   any          `optional:"true"`
+			}{},
+			want: []string{
+				"Invalid field Value",
+				"Invalid field Any",
+			},
+		},
+		"Formats": {
+			model: struct {
+				Count    types.Int64  `valid:"duration"`
+				Interval types.String `valid:"duration(1h)"`
+				Waits    types.List   `elem:"string" valid:"each(rfc3339)"`
+			}{},
+			want: []string{
+				"Invalid valid tag on field Count",
+				"Invalid valid tag on field Interval",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, diags := NewE(test.model)
+
+			got := []string{}
+			for _, d := range diags.Errors() {
+				got = append(got, d.Summary())
+			}
+
+			if diff := deep.Equal(got, append([]string{}, test.want...)); diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, test.want, diff)
+			}
+		})
+	}
+}
+
+func TestCheckDefaults(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		t       attr.Type
+		dv      attr.Value
+		wantErr bool
+	}{
+		"Int64": {
+			t:  types.Int64Type,
+			dv: types.Int64{Value: 3},
+		},
+		"NumberForInt64": {
+			t:       types.Int64Type,
+			dv:      types.Number{Value: big.NewFloat(3)},
+			wantErr: true,
+		},
+		"StringForBool": {
+			t:       types.BoolType,
+			dv:      types.String{Value: "true"},
+			wantErr: true,
+		},
+		"List": {
+			t:  types.ListType{ElemType: types.StringType},
+			dv: types.List{ElemType: types.StringType, Elems: []attr.Value{}},
+		},
+		"ListElem": {
+			t:       types.ListType{ElemType: types.Int64Type},
+			dv:      types.List{ElemType: types.NumberType, Elems: []attr.Value{}},
+			wantErr: true,
+		},
+		"Custom": {
+			t:  testARNType{Type: types.StringType},
+			dv: testARN{Value: types.String{Value: "arn"}},
+		},
+		"CustomMismatch": {
+			t:       testARNType{Type: types.StringType},
+			dv:      types.Int64{Value: 3},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var diags diag.Diagnostics
+			checkDefaults([]tfsdk.AttributePlanModifier{resource.RequiresReplace(), DefaultValue(test.dv)}, test.t, "Test", &diags)
+
+			if diags.HasError() != test.wantErr {
+				t.Errorf("unexpected diagnostics: %v", diags)
+			}
+		})
+	}
+}
+
+func TestReplacePlanModifier(t *testing.T) {
+	t.Parallel()
+
+	RegisterReplaceIf("testengine", func(_ context.Context, _, _ attr.Value, _ path.Path) (bool, diag.Diagnostics) {
+		return true, nil
+	}, "If the engine changes family, Terraform will destroy and recreate the resource.")
+
+	tests := map[string]struct {
+		tag      string
+		attrType string
+		want     string
+		wantErr  bool
+	}{
+		"Always": {
+			tag:      "replace",
+			attrType: "types.String",
+			want:     "If the value of this attribute changes, Terraform will destroy and recreate the resource.",
+		},
+		"IfSet": {
+			tag:      "replace(ifset)",
+			attrType: "types.String",
+			want:     "If the value of this attribute changes after it is set, Terraform will destroy and recreate the resource.",
+		},
+		"IfConfigured": {
+			tag:      "replace(ifconfigured)",
+			attrType: SpecialTypeBlock,
+			want:     "If the value of this attribute changes to another configured value, Terraform will destroy and recreate the resource.",
+		},
+		"DecreaseNumber": {
+			tag:      "replace(decrease)",
+			attrType: "types.Int64",
+			want:     "If the value of this attribute decreases, Terraform will destroy and recreate the resource.",
+		},
+		"DecreaseSet": {
+			tag:      "replace(decrease)",
+			attrType: "types.SetType",
+			want:     "If the number of items in this attribute decreases, Terraform will destroy and recreate the resource.",
+		},
+		"Func": {
+			tag:      "replace(func=testengine)",
+			attrType: "types.String",
+			want:     "If the engine changes family, Terraform will destroy and recreate the resource.",
+		},
+		"DecreaseString": {
+			tag:      "replace(decrease)",
+			attrType: "types.String",
+			wantErr:  true,
+		},
+		"UnknownCondition": {
+			tag:      "replace(sometimes)",
+			attrType: "types.String",
+			wantErr:  true,
+		},
+		"UnknownFunc": {
+			tag:      "replace(func=missing)",
+			attrType: "types.String",
+			wantErr:  true,
+		},
+		"UnknownKey": {
+			tag:      "replace(if=set)",
+			attrType: "types.String",
+			wantErr:  true,
+		},
+		"TwoConditions": {
+			tag:      "replace(ifset,decrease)",
+			attrType: "types.Int64",
+			wantErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			calls, err := parseTagValue(test.tag)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var diags diag.Diagnostics
+			pm := replacePlanModifier(calls[0], test.attrType, "Test", &diags)
+
+			if test.wantErr {
+				if !diags.HasError() || pm != nil {
+					t.Errorf("expected an error, got %v", diags)
+				}
+				return
+			}
+
+			if diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+
+			if got := pm.Description(context.Background()); got != test.want {
+				t.Errorf("unexpected description:\ngot %s\nexpected %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDefaultFrom(t *testing.T) {
+	t.Parallel()
+
+	schm, diags := NewE(struct {
+		Name   types.String `required:"true"`
+		Alias  types.String `optional:"true" computed:"true" pmods:"default(attr=Name)"`
+		Region types.String `optional:"true" computed:"true" pmods:"default(env=AWS_REGION)"`
+	}{})
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	want := map[string]string{
+		"alias":  "Sets the default value from the <.name attribute if the attribute is not set",
+		"region": "Sets the default value from the AWS_REGION environment variable if the attribute is not set",
+	}
+
+	for name, desc := range want {
+		pms := schm.Attributes[name].PlanModifiers
+		if len(pms) != 1 {
+			t.Errorf("%s: expected 1 plan modifier, got %d", name, len(pms))
+			continue
+		}
+
+		if got := pms[0].Description(context.Background()); got != desc {
+			t.Errorf("%s: unexpected description:\ngot %s\nexpected %s", name, got, desc)
+		}
 	}
 }
 
@@ -3,9 +3,14 @@ package mdlschm
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 // defaultValuePlanModifier specifies a default value (attr.Value) for an attribute.
@@ -14,7 +19,7 @@ type defaultValuePlanModifier struct {
 }
 
 func DefaultValue(v attr.Value) tfsdk.AttributePlanModifier {
-	return &defaultValuePlanModifier{v}
+	return &defaultValuePlanModifier{DefaultValue: v}
 }
 
 var _ tfsdk.AttributePlanModifier = (*defaultValuePlanModifier)(nil)
@@ -41,3 +46,192 @@ func (apm *defaultValuePlanModifier) Modify(_ context.Context, req tfsdk.ModifyA
 
 	res.AttributePlan = apm.DefaultValue
 }
+
+// DefaultValueFunc returns the default value for an attribute that is not
+// set. A nil or null value leaves the attribute unset.
+type DefaultValueFunc func(ctx context.Context, req tfsdk.ModifyAttributePlanRequest) (attr.Value, diag.Diagnostics)
+
+// defaultFuncPlanModifier specifies a default value for an attribute from a
+// function called when planning.
+type defaultFuncPlanModifier struct {
+	Func DefaultValueFunc
+	Desc string
+}
+
+// DefaultFunc returns a plan modifier that sets the value of an attribute that
+// is not set to the value returned by f, eg, from provider-level settings.
+func DefaultFunc(f DefaultValueFunc) tfsdk.AttributePlanModifier {
+	return &defaultFuncPlanModifier{
+		Func: f,
+		Desc: "Sets a default value if the attribute is not set",
+	}
+}
+
+// DefaultFromEnv returns a plan modifier that sets the value of an attribute
+// that is not set to the value of the environment variable name, if that is
+// set. Values other than strings are parsed as in default(...) tags, eg, 3 or
+// [a,b].
+func DefaultFromEnv(name string) tfsdk.AttributePlanModifier {
+	return defaultFromEnv(name)
+}
+
+func defaultFromEnv(name string) *defaultFuncPlanModifier {
+	return &defaultFuncPlanModifier{
+		Func: func(ctx context.Context, req tfsdk.ModifyAttributePlanRequest) (attr.Value, diag.Diagnostics) {
+			s, ok := os.LookupEnv(name)
+			if !ok {
+				return nil, nil
+			}
+
+			t, diags := req.Plan.Schema.TypeAtPath(ctx, req.AttributePath)
+			if diags.HasError() {
+				return nil, diags
+			}
+
+			v, err := defaultValue(s, t)
+			if err != nil {
+				diags.AddAttributeError(req.AttributePath, "Invalid default value", fmt.Sprintf("The %s environment variable (%s) %s.", name, s, err))
+				return nil, diags
+			}
+
+			return v, diags
+		},
+		Desc: fmt.Sprintf("Sets the default value from the %s environment variable if the attribute is not set", name),
+	}
+}
+
+// DefaultFromAttribute returns a plan modifier that sets the value of an
+// attribute that is not set to the planned value of the attribute matching
+// expr, eg, path.MatchRelative().AtParent().AtName("name") for a sibling. The
+// planned value is from before the other attribute's own plan modifiers.
+func DefaultFromAttribute(expr path.Expression) tfsdk.AttributePlanModifier {
+	return defaultFromAttribute(expr)
+}
+
+func defaultFromAttribute(expr path.Expression) *defaultFuncPlanModifier {
+	return &defaultFuncPlanModifier{
+		Func: func(ctx context.Context, req tfsdk.ModifyAttributePlanRequest) (attr.Value, diag.Diagnostics) {
+			paths, diags := req.Plan.PathMatches(ctx, req.AttributePathExpression.Merge(expr))
+			if diags.HasError() {
+				return nil, diags
+			}
+
+			if len(paths) != 1 {
+				diags.AddAttributeError(req.AttributePath, "Invalid default value", fmt.Sprintf("The default is copied from %s, which matches %d attributes rather than 1.", expr, len(paths)))
+				return nil, diags
+			}
+
+			var v attr.Value
+			diags.Append(req.Plan.GetAttribute(ctx, paths[0], &v)...)
+
+			return v, diags
+		},
+		Desc: fmt.Sprintf("Sets the default value from the %s attribute if the attribute is not set", expr),
+	}
+}
+
+var _ tfsdk.AttributePlanModifier = (*defaultFuncPlanModifier)(nil)
+
+func (apm *defaultFuncPlanModifier) Description(ctx context.Context) string {
+	return apm.MarkdownDescription(ctx)
+}
+
+func (apm *defaultFuncPlanModifier) MarkdownDescription(_ context.Context) string {
+	return apm.Desc
+}
+
+func (apm *defaultFuncPlanModifier) Modify(ctx context.Context, req tfsdk.ModifyAttributePlanRequest, res *tfsdk.ModifyAttributePlanResponse) {
+	if !req.AttributeConfig.IsNull() {
+		return
+	}
+
+	// a previous plan modifier has already set the plan
+	if !req.AttributePlan.IsUnknown() && !req.AttributePlan.IsNull() {
+		return
+	}
+
+	v, diags := apm.Func(ctx, req)
+	res.Diagnostics.Append(diags...)
+
+	if diags.HasError() || v == nil || v.IsNull() {
+		return
+	}
+
+	res.AttributePlan = v
+}
+
+// replaceIfSet requires replacement when an attribute that was set changes,
+// for replace(ifset). Setting an unset attribute does not.
+func replaceIfSet(_ context.Context, state, _ attr.Value, _ path.Path) (bool, diag.Diagnostics) {
+	return !state.IsNull(), nil
+}
+
+// replaceIfConfigured requires replacement when an attribute changes to a
+// configured value, for replace(ifconfigured). Removing it does not.
+func replaceIfConfigured(_ context.Context, _, config attr.Value, _ path.Path) (bool, diag.Diagnostics) {
+	return !config.IsNull(), nil
+}
+
+// replaceIfDecrease requires replacement when a number gets smaller or a
+// list, set or map loses items, for replace(decrease).
+func replaceIfDecrease(ctx context.Context, state, config attr.Value, p path.Path) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// custom types are compared by their Terraform values
+	sv, err := state.ToTerraformValue(ctx)
+	if err != nil {
+		diags.AddAttributeError(p, "Invalid value", err.Error())
+		return false, diags
+	}
+
+	cv, err := config.ToTerraformValue(ctx)
+	if err != nil {
+		diags.AddAttributeError(p, "Invalid value", err.Error())
+		return false, diags
+	}
+
+	if sv.IsNull() || cv.IsNull() || !sv.IsKnown() || !cv.IsKnown() {
+		return false, diags
+	}
+
+	if sv.Type().Is(tftypes.Number) {
+		var s, c big.Float
+		if err := sv.As(&s); err != nil {
+			diags.AddAttributeError(p, "Invalid value", err.Error())
+			return false, diags
+		}
+		if err := cv.As(&c); err != nil {
+			diags.AddAttributeError(p, "Invalid value", err.Error())
+			return false, diags
+		}
+
+		return c.Cmp(&s) < 0, diags
+	}
+
+	s, err := valueLen(sv)
+	if err != nil {
+		diags.AddAttributeError(p, "Invalid value", err.Error())
+		return false, diags
+	}
+
+	c, err := valueLen(cv)
+	if err != nil {
+		diags.AddAttributeError(p, "Invalid value", err.Error())
+		return false, diags
+	}
+
+	return c < s, diags
+}
+
+// valueLen returns the number of items in a list, set or map.
+func valueLen(v tftypes.Value) (int, error) {
+	if v.Type().Is(tftypes.Map{}) {
+		m := map[string]tftypes.Value{}
+		err := v.As(&m)
+		return len(m), err
+	}
+
+	l := []tftypes.Value{}
+	err := v.As(&l)
+	return len(l), err
+}
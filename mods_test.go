@@ -0,0 +1,222 @@
+package mdlschm
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestReplaceIf(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		f      resource.RequiresReplaceIfFunc
+		state  attr.Value
+		config attr.Value
+		want   bool
+	}{
+		"IfSetChanged": {
+			f:      replaceIfSet,
+			state:  types.String{Value: "a"},
+			config: types.String{Value: "b"},
+			want:   true,
+		},
+		"IfSetRemoved": {
+			f:      replaceIfSet,
+			state:  types.String{Value: "a"},
+			config: types.String{Null: true},
+			want:   true,
+		},
+		"IfSetAdded": {
+			f:      replaceIfSet,
+			state:  types.String{Null: true},
+			config: types.String{Value: "b"},
+		},
+		"IfConfiguredChanged": {
+			f:      replaceIfConfigured,
+			state:  types.String{Value: "a"},
+			config: types.String{Value: "b"},
+			want:   true,
+		},
+		"IfConfiguredRemoved": {
+			f:      replaceIfConfigured,
+			state:  types.String{Value: "a"},
+			config: types.String{Null: true},
+		},
+		"DecreaseInt64": {
+			f:      replaceIfDecrease,
+			state:  types.Int64{Value: 5},
+			config: types.Int64{Value: 3},
+			want:   true,
+		},
+		"IncreaseInt64": {
+			f:      replaceIfDecrease,
+			state:  types.Int64{Value: 5},
+			config: types.Int64{Value: 8},
+		},
+		"DecreaseNumber": {
+			f:      replaceIfDecrease,
+			state:  types.Number{Value: big.NewFloat(1.5)},
+			config: types.Number{Value: big.NewFloat(1.25)},
+			want:   true,
+		},
+		"DecreaseUnknown": {
+			f:      replaceIfDecrease,
+			state:  types.Int64{Value: 5},
+			config: types.Int64{Unknown: true},
+		},
+		"DecreaseList": {
+			f:      replaceIfDecrease,
+			state:  types.List{ElemType: types.StringType, Elems: []attr.Value{types.String{Value: "a"}, types.String{Value: "b"}}},
+			config: types.List{ElemType: types.StringType, Elems: []attr.Value{types.String{Value: "c"}}},
+			want:   true,
+		},
+		"IncreaseMap": {
+			f:      replaceIfDecrease,
+			state:  types.Map{ElemType: types.StringType, Elems: map[string]attr.Value{"a": types.String{Value: "a"}}},
+			config: types.Map{ElemType: types.StringType, Elems: map[string]attr.Value{"a": types.String{Value: "a"}, "b": types.String{Value: "b"}}},
+		},
+		"DecreaseMap": {
+			f:      replaceIfDecrease,
+			state:  types.Map{ElemType: types.StringType, Elems: map[string]attr.Value{"a": types.String{Value: "a"}, "b": types.String{Value: "b"}}},
+			config: types.Map{ElemType: types.StringType, Elems: map[string]attr.Value{}},
+			want:   true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, diags := test.f(context.Background(), test.state, test.config, path.Root("test"))
+			if diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+
+			if got != test.want {
+				t.Errorf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDefaultFunc(t *testing.T) {
+	t.Setenv("MDLSCHM_TEST_REGION", "us-west-2")
+	t.Setenv("MDLSCHM_TEST_COUNT", "3")
+	t.Setenv("MDLSCHM_TEST_BAD_COUNT", "three")
+
+	ctx := context.Background()
+
+	schm := tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"region": {Type: types.StringType, Optional: true, Computed: true},
+			"count":  {Type: types.Int64Type, Optional: true, Computed: true},
+			"name":   {Type: types.StringType, Required: true},
+			"alias":  {Type: types.StringType, Optional: true, Computed: true},
+		},
+	}
+
+	plan := tfsdk.Plan{
+		Schema: schm,
+		Raw: tftypes.NewValue(schm.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"region": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			"count":  tftypes.NewValue(tftypes.Number, tftypes.UnknownValue),
+			"name":   tftypes.NewValue(tftypes.String, "web"),
+			"alias":  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}),
+	}
+
+	tests := map[string]struct {
+		pm      tfsdk.AttributePlanModifier
+		attr    string
+		config  attr.Value
+		want    attr.Value
+		wantErr bool
+	}{
+		"EnvString": {
+			pm:   DefaultFromEnv("MDLSCHM_TEST_REGION"),
+			attr: "region",
+			want: types.String{Value: "us-west-2"},
+		},
+		"EnvInt64": {
+			pm:   DefaultFromEnv("MDLSCHM_TEST_COUNT"),
+			attr: "count",
+			want: types.Int64{Value: 3},
+		},
+		"EnvUnset": {
+			pm:   DefaultFromEnv("MDLSCHM_TEST_MISSING"),
+			attr: "region",
+			want: types.String{Unknown: true},
+		},
+		"EnvInvalid": {
+			pm:      DefaultFromEnv("MDLSCHM_TEST_BAD_COUNT"),
+			attr:    "count",
+			want:    types.Int64{Unknown: true},
+			wantErr: true,
+		},
+		"Configured": {
+			pm:     DefaultFromEnv("MDLSCHM_TEST_REGION"),
+			attr:   "region",
+			config: types.String{Value: "eu-west-1"},
+			want:   types.String{Unknown: true},
+		},
+		"Attribute": {
+			pm:   DefaultFromAttribute(path.MatchRelative().AtParent().AtName("name")),
+			attr: "alias",
+			want: types.String{Value: "web"},
+		},
+		"Func": {
+			pm: DefaultFunc(func(_ context.Context, _ tfsdk.ModifyAttributePlanRequest) (attr.Value, diag.Diagnostics) {
+				return types.String{Value: "provider-region"}, nil
+			}),
+			attr: "region",
+			want: types.String{Value: "provider-region"},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			at := schm.Attributes[test.attr].Type
+
+			config := test.config
+			if config == nil {
+				config, _ = at.ValueFromTerraform(ctx, tftypes.NewValue(at.TerraformType(ctx), nil))
+			}
+
+			unknown, _ := at.ValueFromTerraform(ctx, tftypes.NewValue(at.TerraformType(ctx), tftypes.UnknownValue))
+
+			req := tfsdk.ModifyAttributePlanRequest{
+				AttributePath:           path.Root(test.attr),
+				AttributePathExpression: path.MatchRoot(test.attr),
+				Plan:                    plan,
+				AttributeConfig:         config,
+				AttributePlan:           unknown,
+			}
+			res := &tfsdk.ModifyAttributePlanResponse{
+				AttributePlan: unknown,
+			}
+
+			test.pm.Modify(ctx, req, res)
+
+			if res.Diagnostics.HasError() != test.wantErr {
+				t.Errorf("unexpected diagnostics: %v", res.Diagnostics)
+			}
+
+			if !res.AttributePlan.Equal(test.want) {
+				t.Errorf("got %s, want %s", res.AttributePlan, test.want)
+			}
+		})
+	}
+}
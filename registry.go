@@ -0,0 +1,307 @@
+package mdlschm
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	typeRegistryMu sync.RWMutex
+	typeRegistry   = make(map[reflect.Type]attr.Type)
+)
+
+// RegisterType maps a Go type to the attr.Type that New uses for fields of
+// that type. The Go type can be a custom attr.Value implementation, such as
+// an ARN or JSON document type, or a named Go type, such as `type Region
+// string`, which otherwise goes by its kind. Slices and string-keyed maps of
+// a registered type become lists (or sets) and maps of the attr.Type.
+// Validators and defaults follow the attr.Type's underlying Terraform type.
+// Register types before calling New, typically in an init function.
+func RegisterType(goType reflect.Type, attrType attr.Type) {
+	typeRegistryMu.Lock()
+	defer typeRegistryMu.Unlock()
+
+	typeRegistry[goType] = attrType
+}
+
+func registeredType(goType reflect.Type) attr.Type {
+	typeRegistryMu.RLock()
+	defer typeRegistryMu.RUnlock()
+
+	return typeRegistry[goType]
+}
+
+// collectionType returns the attr.Type for a registered Go type or a slice or
+// string-keyed map of one, or nil if the type is not registered.
+func collectionType(goType reflect.Type, tags string) attr.Type {
+	if t := registeredType(goType); t != nil {
+		return t
+	}
+
+	switch goType.Kind() {
+	case reflect.Slice:
+		et := registeredType(goType.Elem())
+		if et == nil {
+			return nil
+		}
+
+		if tagValue(TagCollection, tags) == TagCollectionSet {
+			return types.SetType{
+				ElemType: et,
+			}
+		}
+
+		return types.ListType{
+			ElemType: et,
+		}
+	case reflect.Map:
+		if goType.Key().Kind() != reflect.String {
+			return nil
+		}
+
+		et := registeredType(goType.Elem())
+		if et == nil {
+			return nil
+		}
+
+		return types.MapType{
+			ElemType: et,
+		}
+	}
+
+	return nil
+}
+
+// ValidatorFactory builds a validator from the arguments of its call in a
+// valid tag, eg, ["iam"] for arn(iam), and the type of the attribute, which is
+// nil for blocks.
+type ValidatorFactory func(args []string, t attr.Type) (tfsdk.AttributeValidator, error)
+
+// PlanModifierFactory builds a plan modifier from the arguments of its call in
+// a pmods tag and the type of the attribute.
+type PlanModifierFactory func(args []string, t attr.Type) (tfsdk.AttributePlanModifier, error)
+
+var (
+	validatorRegistryMu sync.RWMutex
+	validatorRegistry   = make(map[string]ValidatorFactory)
+
+	planModifierRegistryMu sync.RWMutex
+	planModifierRegistry   = make(map[string]PlanModifierFactory)
+
+	replaceIfRegistryMu sync.RWMutex
+	replaceIfRegistry   = make(map[string]replaceIf)
+)
+
+// replaceIf is a condition registered for replace(func=name).
+type replaceIf struct {
+	f           resource.RequiresReplaceIfFunc
+	description string
+}
+
+// builtinValidators and builtinPlanModifiers are the tag names that cannot be
+// registered.
+var (
+	builtinValidators = []string{
+		TagValidatorBetween, TagValidatorOneOf, TagValidatorNoneOf,
+		TagValidatorRegex, TagValidatorLenAtLeast, TagValidatorLenAtMost,
+		TagValidatorPrefix, TagValidatorSuffix, TagValidatorContains,
+		TagValidatorDuration, TagValidatorRFC3339, TagValidatorBase64,
+		TagValidatorConflicts, TagValidatorExactlyOneOf, TagValidatorAtLeastOneOf, TagValidatorAlsoRequires,
+		TagValidatorEach, TagValidatorKeys,
+	}
+	builtinPlanModifiers = []string{
+		TagPlanModifierReplace, TagPlanModifierDefault, TagPlanModifierUSFU,
+	}
+)
+
+// RegisterValidator makes a validator available in valid tags under name, eg,
+// `valid:"arn(iam)"` calls the factory registered as arn with ["iam"]. The
+// factory's error is reported against the field. Registering a built-in
+// name, such as between, or a name that is not a valid tag name panics.
+// Register validators before calling New, typically in an init function.
+func RegisterValidator(name string, factory ValidatorFactory) {
+	checkRegisteredName(name, builtinValidators)
+
+	validatorRegistryMu.Lock()
+	defer validatorRegistryMu.Unlock()
+
+	validatorRegistry[name] = factory
+}
+
+// RegisterPlanModifier makes a plan modifier available in pmods tags under
+// name, eg, `pmods:"jsonequiv"`, in the same way as RegisterValidator.
+func RegisterPlanModifier(name string, factory PlanModifierFactory) {
+	checkRegisteredName(name, builtinPlanModifiers)
+
+	planModifierRegistryMu.Lock()
+	defer planModifierRegistryMu.Unlock()
+
+	planModifierRegistry[name] = factory
+}
+
+// RegisterReplaceIf makes f available as the condition of a replace plan
+// modifier in pmods tags under name, eg, `pmods:"replace(func=engine)"`. The
+// description appears in the plan modifier and documentation, eg, "If the
+// engine changes family, Terraform will destroy and recreate the resource."
+func RegisterReplaceIf(name string, f resource.RequiresReplaceIfFunc, description string) {
+	replaceIfRegistryMu.Lock()
+	defer replaceIfRegistryMu.Unlock()
+
+	replaceIfRegistry[name] = replaceIf{f: f, description: description}
+}
+
+func registeredReplaceIf(name string) (resource.RequiresReplaceIfFunc, string) {
+	replaceIfRegistryMu.RLock()
+	defer replaceIfRegistryMu.RUnlock()
+
+	r := replaceIfRegistry[name]
+	return r.f, r.description
+}
+
+func registeredReplaceIfNames() []string {
+	replaceIfRegistryMu.RLock()
+	defer replaceIfRegistryMu.RUnlock()
+
+	return sortedKeys(replaceIfRegistry)
+}
+
+func checkRegisteredName(name string, builtins []string) {
+	if isBuiltin(name, builtins) {
+		panic(fmt.Sprintf("mdlschm: %s is built in and cannot be registered", name))
+	}
+
+	p := &tagParser{src: name}
+	if p.name() != name || name == "" {
+		panic(fmt.Sprintf("mdlschm: %q is not a valid tag name", name))
+	}
+}
+
+func registeredValidator(name string) ValidatorFactory {
+	validatorRegistryMu.RLock()
+	defer validatorRegistryMu.RUnlock()
+
+	return validatorRegistry[name]
+}
+
+func registeredPlanModifier(name string) PlanModifierFactory {
+	planModifierRegistryMu.RLock()
+	defer planModifierRegistryMu.RUnlock()
+
+	return planModifierRegistry[name]
+}
+
+// registeredArgs returns the values of a call to a registered name, which
+// cannot be key=value.
+func registeredArgs(c tagCall, key, fieldPath string, diags *diag.Diagnostics) ([]string, bool) {
+	for _, a := range c.args {
+		if a.key != "" {
+			addTagError(diags, fieldPath, key, fmt.Sprintf("%s does not take key=value args at column %d", c.name, a.pos+1))
+			return nil, false
+		}
+	}
+
+	return c.values(), true
+}
+
+// isBuiltin reports whether name is one of builtins.
+func isBuiltin(name string, builtins []string) bool {
+	for _, b := range builtins {
+		if name == b {
+			return true
+		}
+	}
+
+	return false
+}
+
+// registeredValidators builds the validators for the calls to registered
+// names in a valid tag. Names that are neither built in nor registered, eg,
+// typos, are errors.
+func registeredValidators(calls []tagCall, t attr.Type, fieldPath string, diags *diag.Diagnostics) []tfsdk.AttributeValidator {
+	vals := []tfsdk.AttributeValidator{}
+
+	for _, c := range calls {
+		f := registeredValidator(c.name)
+		if f == nil {
+			if !isBuiltin(c.name, builtinValidators) {
+				addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("unknown validator %s at column %d, which is not built in or registered with RegisterValidator", c.name, c.pos+1))
+			}
+			continue
+		}
+
+		args, ok := registeredArgs(c, TagValidators, fieldPath, diags)
+		if !ok {
+			continue
+		}
+
+		v, err := f(args, t)
+		if err != nil {
+			addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s: %s at column %d", c.name, err, c.pos+1))
+			continue
+		}
+
+		if isNil(v) {
+			addTagError(diags, fieldPath, TagValidators, fmt.Sprintf("%s: factory returned no validator at column %d", c.name, c.pos+1))
+			continue
+		}
+
+		vals = append(vals, v)
+	}
+
+	return vals
+}
+
+// registeredPlanModifiers builds the plan modifiers for the calls to
+// registered names in a pmods tag. Names that are neither built in nor
+// registered are errors.
+func registeredPlanModifiers(calls []tagCall, t attr.Type, fieldPath string, diags *diag.Diagnostics) []tfsdk.AttributePlanModifier {
+	pms := []tfsdk.AttributePlanModifier{}
+
+	for _, c := range calls {
+		f := registeredPlanModifier(c.name)
+		if f == nil {
+			if !isBuiltin(c.name, builtinPlanModifiers) {
+				addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("unknown plan modifier %s at column %d, which is not built in or registered with RegisterPlanModifier", c.name, c.pos+1))
+			}
+			continue
+		}
+
+		args, ok := registeredArgs(c, TagPlanModifiers, fieldPath, diags)
+		if !ok {
+			continue
+		}
+
+		pm, err := f(args, t)
+		if err != nil {
+			addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s: %s at column %d", c.name, err, c.pos+1))
+			continue
+		}
+
+		if isNil(pm) {
+			addTagError(diags, fieldPath, TagPlanModifiers, fmt.Sprintf("%s: factory returned no plan modifier at column %d", c.name, c.pos+1))
+			continue
+		}
+
+		pms = append(pms, pm)
+	}
+
+	return pms
+}
+
+// isNil reports whether v, from a factory, is nil or a nil pointer, which
+// would only panic once the framework used it.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
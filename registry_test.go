@@ -0,0 +1,276 @@
+package mdlschm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type testRegion string
+
+type testARNType struct {
+	attr.Type
+}
+
+func (t testARNType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	v, err := t.Type.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return testARN{Value: v}, nil
+}
+
+type testARN struct {
+	attr.Value
+}
+
+func TestRegisterType(t *testing.T) {
+	RegisterType(reflect.TypeOf(testRegion("")), types.StringType)
+	RegisterType(reflect.TypeOf(testARN{}), testARNType{Type: types.StringType})
+
+	model := struct {
+		Region  testRegion   `required:"true" valid:"oneof(us-west-2,us-east-1)"`
+		Regions []testRegion `valid:"between(1,3)"`
+		ARN     testARN      `computed:"true" valid:"between(7,2048)" pmods:"default(unknown)"`
+	}{}
+
+	want := tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"region": {
+				Type:     types.StringType,
+				Required: true,
+				Validators: []tfsdk.AttributeValidator{
+					stringvalidator.OneOf("us-west-2", "us-east-1"),
+				},
+			},
+			"regions": {
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				Validators: []tfsdk.AttributeValidator{
+					listvalidator.SizeBetween(1, 3),
+				},
+			},
+			"arn": {
+				Type:     testARNType{Type: types.StringType},
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					DefaultValue(testARN{Value: types.String{Value: "unknown"}}),
+				},
+				Validators: []tfsdk.AttributeValidator{
+					stringvalidator.LengthBetween(7, 2048),
+				},
+			},
+		},
+	}
+
+	got := New(model)
+
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, want, diff)
+	}
+}
+
+func init() {
+	RegisterValidator("testarn", func(args []string, t attr.Type) (tfsdk.AttributeValidator, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("requires 1 service, got %d", len(args))
+		}
+
+		if t == nil || baseType(t) != "types.String" {
+			return nil, errors.New("is only for strings")
+		}
+
+		return StringHasPrefix(fmt.Sprintf("arn:aws:%s:", args[0])), nil
+	})
+
+	RegisterPlanModifier("testjsonequiv", func(args []string, _ attr.Type) (tfsdk.AttributePlanModifier, error) {
+		if len(args) > 0 {
+			return nil, errors.New("takes no args")
+		}
+
+		return resource.UseStateForUnknown(), nil
+	})
+
+	RegisterValidator("testnilvalidator", func(_ []string, _ attr.Type) (tfsdk.AttributeValidator, error) {
+		return nil, nil
+	})
+
+	RegisterPlanModifier("testnilplanmodifier", func(_ []string, _ attr.Type) (tfsdk.AttributePlanModifier, error) {
+		var pm *defaultValuePlanModifier
+		return pm, nil
+	})
+
+	RegisterPlanModifier("testnumberdefault", func(args []string, _ attr.Type) (tfsdk.AttributePlanModifier, error) {
+		return DefaultValue(types.Number{Value: big.NewFloat(1)}), nil
+	})
+}
+
+func TestRegisterValidator(t *testing.T) {
+	t.Parallel()
+
+	model := struct {
+		Role   types.String   `valid:"testarn(iam),lenatmost(2048)" pmods:"replace,testjsonequiv"`
+		Bucket []types.String `valid:"each(testarn(s3))"`
+	}{}
+
+	want := tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"role": {
+				Type:     types.StringType,
+				Optional: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					resource.RequiresReplace(),
+					resource.UseStateForUnknown(),
+				},
+				Validators: []tfsdk.AttributeValidator{
+					stringvalidator.LengthAtMost(2048),
+					StringHasPrefix("arn:aws:iam:"),
+				},
+			},
+			"bucket": {
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				Validators: []tfsdk.AttributeValidator{
+					listvalidator.ValuesAre(StringHasPrefix("arn:aws:s3:")),
+				},
+			},
+		},
+	}
+
+	got, diags := NewE(model)
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, want, diff)
+	}
+
+	wantNotes := []string{
+		"Must be at most 2048 characters long.",
+		"Value must start with \"arn:aws:iam:\".",
+		"Changing this forces a new resource.",
+		"Once set, the value of this attribute in state will not change.",
+	}
+
+	field, _ := reflect.TypeOf(model).FieldByName("Role")
+	if diff := deep.Equal(tagNotes("role", string(field.Tag), types.StringType, nil), wantNotes); diff != nil {
+		t.Errorf("unexpected notes: %v", diff)
+	}
+}
+
+func TestRegisterValidatorErrors(t *testing.T) {
+	t.Parallel()
+
+	model := struct {
+		Role   types.String `valid:"testarn()"`
+		User   types.String `valid:"testarn(service=iam)"`
+		Count  types.Int64  `valid:"testarn(iam)"`
+		Policy types.String `pmods:"testjsonequiv(strict)"`
+		Size   types.Int64  `pmods:"testnumberdefault"`
+		Amount types.Number `pmods:"testnumberdefault"`
+		Zone   types.String `valid:"testnilvalidator"`
+		Owner  types.String `pmods:"testnilplanmodifier"`
+	}{}
+
+	want := []string{
+		"Invalid valid tag on field Role",
+		"Invalid valid tag on field User",
+		"Invalid valid tag on field Count",
+		"Invalid pmods tag on field Policy",
+		"Invalid pmods tag on field Size",
+		"Invalid valid tag on field Zone",
+		"Invalid pmods tag on field Owner",
+	}
+
+	_, diags := NewE(model)
+
+	got := []string{}
+	for _, d := range diags.Errors() {
+		got = append(got, d.Summary())
+	}
+
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, want, diff)
+	}
+}
+
+func TestUnknownNames(t *testing.T) {
+	t.Parallel()
+
+	model := struct {
+		Size   types.Int64  `valid:"betwen(1,2)"`
+		Role   types.String `valid:"testunregistered(iam)"`
+		Policy types.String `pmods:"testunregisteredequiv"`
+		Name   types.String `pmods:"replac"`
+		Zones  types.List   `elem:"string" valid:"each(oneof(a,b),lenatmots(8))"`
+	}{}
+
+	want := []string{
+		"Invalid valid tag on field Size: unknown validator betwen at column 1, which is not built in or registered with RegisterValidator",
+		"Invalid valid tag on field Role: unknown validator testunregistered at column 1, which is not built in or registered with RegisterValidator",
+		"Invalid pmods tag on field Policy: unknown plan modifier testunregisteredequiv at column 1, which is not built in or registered with RegisterPlanModifier",
+		"Invalid pmods tag on field Name: unknown plan modifier replac at column 1, which is not built in or registered with RegisterPlanModifier",
+		"Invalid valid tag on field Zones: unknown validator lenatmots at column 17, which is not built in or registered with RegisterValidator",
+	}
+
+	_, diags := NewE(model)
+
+	got := []string{}
+	for _, d := range diags.Errors() {
+		got = append(got, fmt.Sprintf("%s: %s", d.Summary(), d.Detail()))
+	}
+
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, want, diff)
+	}
+}
+
+func TestRegisterValidatorPanics(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]func(){
+		"BuiltinValidator": func() {
+			RegisterValidator(TagValidatorBetween, nil)
+		},
+		"BuiltinPlanModifier": func() {
+			RegisterPlanModifier(TagPlanModifierDefault, nil)
+		},
+		"BadName": func() {
+			RegisterValidator("arn-iam", nil)
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+
+			test()
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package mdlschm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const (
+	jsonDescriptionKindMarkdown = "markdown"
+
+	jsonNestingList   = "list"
+	jsonNestingMap    = "map"
+	jsonNestingSet    = "set"
+	jsonNestingSingle = "single"
+)
+
+// jsonProviderSchemas is the output of `terraform providers schema -json`.
+type jsonProviderSchemas struct {
+	ProviderSchemas map[string]struct {
+		Provider          *jsonSchema            `json:"provider"`
+		ResourceSchemas   map[string]*jsonSchema `json:"resource_schemas"`
+		DataSourceSchemas map[string]*jsonSchema `json:"data_source_schemas"`
+	} `json:"provider_schemas"`
+}
+
+type jsonSchema struct {
+	Version int64     `json:"version"`
+	Block   jsonBlock `json:"block"`
+}
+
+type jsonBlock struct {
+	Attributes      map[string]jsonAttribute `json:"attributes"`
+	BlockTypes      map[string]jsonBlockType `json:"block_types"`
+	Description     string                   `json:"description"`
+	DescriptionKind string                   `json:"description_kind"`
+	Deprecated      bool                     `json:"deprecated"`
+}
+
+type jsonAttribute struct {
+	Type            json.RawMessage `json:"type"`
+	NestedType      *jsonNestedType `json:"nested_type"`
+	Description     string          `json:"description"`
+	DescriptionKind string          `json:"description_kind"`
+	Required        bool            `json:"required"`
+	Optional        bool            `json:"optional"`
+	Computed        bool            `json:"computed"`
+	Sensitive       bool            `json:"sensitive"`
+	Deprecated      bool            `json:"deprecated"`
+}
+
+type jsonNestedType struct {
+	Attributes  map[string]jsonAttribute `json:"attributes"`
+	NestingMode string                   `json:"nesting_mode"`
+}
+
+type jsonBlockType struct {
+	NestingMode string    `json:"nesting_mode"`
+	Block       jsonBlock `json:"block"`
+	MinItems    int64     `json:"min_items"`
+	MaxItems    int64     `json:"max_items"`
+}
+
+// jsonDeprecationMessage stands in for deprecation messages, which the JSON
+// schema does not include.
+const jsonDeprecationMessage = "Deprecated"
+
+// SchemaFromJSON finds the resource or data source named typeName in the
+// output of `terraform providers schema -json` and converts it into a
+// tfsdk.Schema. The JSON schema has no validators, plan modifiers or
+// deprecation messages, and its numbers are all types.NumberType.
+func SchemaFromJSON(data []byte, typeName string) (tfsdk.Schema, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	var ps jsonProviderSchemas
+	if err := json.Unmarshal(data, &ps); err != nil {
+		diags.AddError("Invalid JSON schema", err.Error())
+		return tfsdk.Schema{}, diags
+	}
+
+	// providers are checked in order so the result does not depend on map order
+	providers := []string{}
+	for k := range ps.ProviderSchemas {
+		providers = append(providers, k)
+	}
+	sort.Strings(providers)
+
+	for _, p := range providers {
+		js, ok := ps.ProviderSchemas[p].ResourceSchemas[typeName]
+		if !ok {
+			js, ok = ps.ProviderSchemas[p].DataSourceSchemas[typeName]
+		}
+		if !ok {
+			continue
+		}
+
+		s := tfsdk.Schema{
+			Version: js.Version,
+		}
+
+		if js.Block.DescriptionKind == jsonDescriptionKindMarkdown {
+			s.MarkdownDescription = js.Block.Description
+		} else {
+			s.Description = js.Block.Description
+		}
+
+		if js.Block.Deprecated {
+			s.DeprecationMessage = jsonDeprecationMessage
+		}
+
+		s.Attributes = jsonAttributes(js.Block.Attributes, typeName, &diags)
+		s.Blocks = jsonBlocks(js.Block.BlockTypes, typeName, &diags)
+
+		return s, diags
+	}
+
+	diags.AddError("Invalid JSON schema", fmt.Sprintf("no resource or data source named %s", typeName))
+	return tfsdk.Schema{}, diags
+}
+
+func jsonAttributes(jas map[string]jsonAttribute, parentPath string, diags *diag.Diagnostics) map[string]tfsdk.Attribute {
+	if len(jas) == 0 {
+		return nil
+	}
+
+	attrs := make(map[string]tfsdk.Attribute)
+
+	for k, ja := range jas {
+		fp := joinFieldPath(parentPath, k)
+
+		a := tfsdk.Attribute{
+			Required:  ja.Required,
+			Optional:  ja.Optional,
+			Computed:  ja.Computed,
+			Sensitive: ja.Sensitive,
+		}
+
+		if ja.DescriptionKind == jsonDescriptionKindMarkdown {
+			a.MarkdownDescription = ja.Description
+		} else {
+			a.Description = ja.Description
+		}
+
+		if ja.Deprecated {
+			a.DeprecationMessage = jsonDeprecationMessage
+		}
+
+		if ja.NestedType != nil {
+			nested := jsonAttributes(ja.NestedType.Attributes, fp, diags)
+
+			switch ja.NestedType.NestingMode {
+			case jsonNestingList:
+				a.Attributes = tfsdk.ListNestedAttributes(nested)
+			case jsonNestingMap:
+				a.Attributes = tfsdk.MapNestedAttributes(nested)
+			case jsonNestingSet:
+				a.Attributes = tfsdk.SetNestedAttributes(nested)
+			case jsonNestingSingle:
+				a.Attributes = tfsdk.SingleNestedAttributes(nested)
+			default:
+				diags.AddError("Invalid JSON schema", fmt.Sprintf("%s: unsupported nesting mode: %s", fp, ja.NestedType.NestingMode))
+				continue
+			}
+
+			attrs[k] = a
+			continue
+		}
+
+		tt, err := tftypes.ParseJSONType(ja.Type)
+		if err != nil {
+			diags.AddError("Invalid JSON schema", fmt.Sprintf("%s: %s", fp, err))
+			continue
+		}
+
+		t, err := frameworkTypeOf(tt)
+		if err != nil {
+			diags.AddError("Invalid JSON schema", fmt.Sprintf("%s: %s", fp, err))
+			continue
+		}
+
+		a.Type = t
+		attrs[k] = a
+	}
+
+	return attrs
+}
+
+func jsonBlocks(jbs map[string]jsonBlockType, parentPath string, diags *diag.Diagnostics) map[string]tfsdk.Block {
+	if len(jbs) == 0 {
+		return nil
+	}
+
+	blocks := make(map[string]tfsdk.Block)
+
+	for k, jb := range jbs {
+		fp := joinFieldPath(parentPath, k)
+
+		b := tfsdk.Block{
+			MinItems: jb.MinItems,
+			MaxItems: jb.MaxItems,
+		}
+
+		switch jb.NestingMode {
+		case jsonNestingList:
+			b.NestingMode = tfsdk.BlockNestingModeList
+		case jsonNestingSet:
+			b.NestingMode = tfsdk.BlockNestingModeSet
+		case jsonNestingSingle:
+			b.NestingMode = tfsdk.BlockNestingModeSingle
+		default:
+			diags.AddError("Invalid JSON schema", fmt.Sprintf("%s: unsupported block nesting mode: %s", fp, jb.NestingMode))
+			continue
+		}
+
+		if jb.Block.DescriptionKind == jsonDescriptionKindMarkdown {
+			b.MarkdownDescription = jb.Block.Description
+		} else {
+			b.Description = jb.Block.Description
+		}
+
+		if jb.Block.Deprecated {
+			b.DeprecationMessage = jsonDeprecationMessage
+		}
+
+		b.Attributes = jsonAttributes(jb.Block.Attributes, fp, diags)
+		b.Blocks = jsonBlocks(jb.Block.BlockTypes, fp, diags)
+
+		blocks[k] = b
+	}
+
+	return blocks
+}
+
+// frameworkTypeOf converts a Terraform type into the framework type used for
+// it. Numbers are always types.NumberType.
+func frameworkTypeOf(tt tftypes.Type) (attr.Type, error) {
+	switch {
+	case tt.Is(tftypes.Bool):
+		return types.BoolType, nil
+	case tt.Is(tftypes.Number):
+		return types.NumberType, nil
+	case tt.Is(tftypes.String):
+		return types.StringType, nil
+	case tt.Is(tftypes.List{}):
+		et, err := frameworkTypeOf(tt.(tftypes.List).ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return types.ListType{ElemType: et}, nil
+	case tt.Is(tftypes.Map{}):
+		et, err := frameworkTypeOf(tt.(tftypes.Map).ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return types.MapType{ElemType: et}, nil
+	case tt.Is(tftypes.Set{}):
+		et, err := frameworkTypeOf(tt.(tftypes.Set).ElementType)
+		if err != nil {
+			return nil, err
+		}
+		return types.SetType{ElemType: et}, nil
+	case tt.Is(tftypes.Object{}):
+		ot := types.ObjectType{
+			AttrTypes: make(map[string]attr.Type),
+		}
+		for k, v := range tt.(tftypes.Object).AttributeTypes {
+			at, err := frameworkTypeOf(v)
+			if err != nil {
+				return nil, err
+			}
+			ot.AttrTypes[k] = at
+		}
+		return ot, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type: %s", tt)
+}
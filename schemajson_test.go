@@ -0,0 +1,155 @@
+package mdlschm
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testProviderSchemaJSON = `{
+  "format_version": "1.0",
+  "provider_schemas": {
+    "registry.terraform.io/example/widget": {
+      "resource_schemas": {
+        "widget_thing": {
+          "version": 1,
+          "block": {
+            "attributes": {
+              "name": {"type": "string", "description": "Name", "description_kind": "plain", "required": true},
+              "ports": {"type": ["set", "number"], "optional": true},
+              "meta": {"type": ["object", {"owner": "string", "size": "number"}], "computed": true, "sensitive": true},
+              "routes": {
+                "nested_type": {
+                  "attributes": {"weight": {"type": "number", "required": true}},
+                  "nesting_mode": "map"
+                },
+                "optional": true
+              }
+            },
+            "block_types": {
+              "rule": {
+                "nesting_mode": "list",
+                "block": {
+                  "attributes": {"port": {"type": "number", "required": true}},
+                  "description": "A rule",
+                  "description_kind": "markdown"
+                },
+                "min_items": 1
+              }
+            },
+            "description_kind": "plain"
+          }
+        }
+      },
+      "data_source_schemas": {
+        "widget_other": {
+          "version": 0,
+          "block": {
+            "attributes": {
+              "id": {"type": "string", "computed": true, "deprecated": true}
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestSchemaFromJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		typeName string
+		want     tfsdk.Schema
+		wantErr  bool
+	}{
+		"Resource": {
+			typeName: "widget_thing",
+			want: tfsdk.Schema{
+				Version: 1,
+				Attributes: map[string]tfsdk.Attribute{
+					"name": {
+						Type:        types.StringType,
+						Required:    true,
+						Description: "Name",
+					},
+					"ports": {
+						Type: types.SetType{
+							ElemType: types.NumberType,
+						},
+						Optional: true,
+					},
+					"meta": {
+						Type: types.ObjectType{
+							AttrTypes: map[string]attr.Type{
+								"owner": types.StringType,
+								"size":  types.NumberType,
+							},
+						},
+						Computed:  true,
+						Sensitive: true,
+					},
+					"routes": {
+						Attributes: tfsdk.MapNestedAttributes(map[string]tfsdk.Attribute{
+							"weight": {
+								Type:     types.NumberType,
+								Required: true,
+							},
+						}),
+						Optional: true,
+					},
+				},
+				Blocks: map[string]tfsdk.Block{
+					"rule": {
+						Attributes: map[string]tfsdk.Attribute{
+							"port": {
+								Type:     types.NumberType,
+								Required: true,
+							},
+						},
+						MarkdownDescription: "A rule",
+						MinItems:            1,
+						NestingMode:         tfsdk.BlockNestingModeList,
+					},
+				},
+			},
+		},
+		"DataSource": {
+			typeName: "widget_other",
+			want: tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"id": {
+						Type:               types.StringType,
+						Computed:           true,
+						DeprecationMessage: jsonDeprecationMessage,
+					},
+				},
+			},
+		},
+		"Missing": {
+			typeName: "widget_missing",
+			wantErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, diags := SchemaFromJSON([]byte(testProviderSchemaJSON), test.typeName)
+
+			if diags.HasError() != test.wantErr {
+				t.Fatalf("got errors %v, want errors %t", diags, test.wantErr)
+			}
+
+			if diff := deep.Equal(got, test.want); diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, test.want, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,623 @@
+package mdlschm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// The valid and pmods tags hold a small language: a comma-separated list of
+// calls, each a name with optional arguments, eg,
+//
+//	valid:"between(1,64),oneof(small,'extra, large')"
+//	pmods:"replace,default(env=REGION)"
+//
+// Arguments are bare text, quoted strings or nested calls, optionally named
+// with key=value. Bare text is trimmed of spaces and may hold balanced
+// parentheses, brackets and braces, eg, default([a,b]). Quoted strings use
+// single or double quotes (written \" in a struct tag) with backslash
+// escapes for quotes and backslash, other backslashes being kept, and are
+// needed for values with commas, parentheses, quotes or edge spaces.
+
+// tagCall is one call in a tag value, eg, between(1,64) or replace.
+type tagCall struct {
+	name    string
+	args    []tagArg
+	hasArgs bool // has parentheses, even if empty
+	pos     int  // byte offset of the name in the tag value
+}
+
+// tagArg is one argument of a tagCall.
+type tagArg struct {
+	key   string   // name in key=value, if any
+	value string   // unquoted value or, for nested calls, their text
+	call  *tagCall // nested call, if any
+	pos   int      // byte offset of the value in the tag value
+}
+
+// tagSyntaxError is a problem parsing a tag, located by byte offset.
+type tagSyntaxError struct {
+	src    string
+	offset int
+	msg    string
+}
+
+func (e *tagSyntaxError) Error() string {
+	return fmt.Sprintf("%s at column %d of %s", e.msg, e.offset+1, e.src)
+}
+
+// values returns the values of the call's arguments.
+func (c tagCall) values() []string {
+	vals := []string{}
+	for _, a := range c.args {
+		vals = append(vals, a.value)
+	}
+
+	return vals
+}
+
+// String returns the call as it could be written in a tag.
+func (c tagCall) String() string {
+	if !c.hasArgs {
+		return c.name
+	}
+
+	args := []string{}
+	for _, a := range c.args {
+		s := quoteTagArg(a.value)
+		if a.call != nil {
+			s = a.call.String()
+		}
+
+		if a.key != "" {
+			s = fmt.Sprintf("%s=%s", a.key, s)
+		}
+
+		args = append(args, s)
+	}
+
+	return fmt.Sprintf("%s(%s)", c.name, strings.Join(args, ","))
+}
+
+// newTagCall returns a call with plain values as arguments.
+func newTagCall(name string, values ...string) tagCall {
+	c := tagCall{name: name, hasArgs: true}
+	for _, v := range values {
+		c.args = append(c.args, tagArg{value: v})
+	}
+
+	return c
+}
+
+// findCall returns the first call named name.
+func findCall(calls []tagCall, name string) (tagCall, bool) {
+	for _, c := range calls {
+		if c.name == name {
+			return c, true
+		}
+	}
+
+	return tagCall{}, false
+}
+
+// hasCall reports whether there is a call named name.
+func hasCall(calls []tagCall, name string) bool {
+	_, ok := findCall(calls, name)
+	return ok
+}
+
+// quoteTagArg returns s as a tag argument, quoted only if needed.
+func quoteTagArg(s string) string {
+	if s != "" && s == strings.TrimSpace(s) && !strings.ContainsAny(s, `,()'"\=`) {
+		return s
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// parseTagValue parses the value of a valid or pmods tag into calls.
+func parseTagValue(src string) ([]tagCall, error) {
+	p := &tagParser{src: src}
+
+	calls := []tagCall{}
+
+	p.skipSpace()
+	if p.done() {
+		return calls, nil
+	}
+
+	for {
+		c, err := p.call()
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, c)
+
+		p.skipSpace()
+		if p.done() {
+			return calls, nil
+		}
+
+		if p.peek() != ',' {
+			return nil, p.errorf(p.pos, "expected , but found %q", p.peek())
+		}
+		p.pos++
+	}
+}
+
+type tagParser struct {
+	src string
+	pos int
+}
+
+func (p *tagParser) done() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *tagParser) peek() byte {
+	return p.src[p.pos]
+}
+
+func (p *tagParser) skipSpace() {
+	for !p.done() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *tagParser) errorf(offset int, format string, a ...any) error {
+	return &tagSyntaxError{src: p.src, offset: offset, msg: fmt.Sprintf(format, a...)}
+}
+
+func isNameByte(b byte, first bool) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (!first && b >= '0' && b <= '9')
+}
+
+// name reads a name, returning "" if there is none.
+func (p *tagParser) name() string {
+	start := p.pos
+	for !p.done() && isNameByte(p.peek(), p.pos == start) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// call parses name or name(args).
+func (p *tagParser) call() (tagCall, error) {
+	p.skipSpace()
+
+	c := tagCall{pos: p.pos}
+
+	if c.name = p.name(); c.name == "" {
+		if p.done() {
+			return c, p.errorf(p.pos, "expected a name but found the end")
+		}
+		return c, p.errorf(p.pos, "expected a name but found %q", p.peek())
+	}
+
+	p.skipSpace()
+	if p.done() || p.peek() != '(' {
+		return c, nil
+	}
+
+	open := p.pos
+	p.pos++
+	c.hasArgs = true
+
+	p.skipSpace()
+	if !p.done() && p.peek() == ')' {
+		p.pos++
+		return c, nil
+	}
+
+	for {
+		a, err := p.arg(open)
+		if err != nil {
+			return c, err
+		}
+		c.args = append(c.args, a)
+
+		p.skipSpace()
+		if p.done() {
+			return c, p.errorf(open, "missing ) for %s(", c.name)
+		}
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return c, nil
+		default:
+			return c, p.errorf(p.pos, "expected , or ) but found %q", p.peek())
+		}
+	}
+}
+
+// arg parses one argument of the call whose parenthesis is at open.
+func (p *tagParser) arg(open int) (tagArg, error) {
+	p.skipSpace()
+
+	a := tagArg{pos: p.pos}
+
+	// key=value and nested calls start with a name
+	start := p.pos
+	if n := p.name(); n != "" {
+		p.skipSpace()
+
+		switch {
+		case !p.done() && p.peek() == '=':
+			a.key = n
+			p.pos++
+			p.skipSpace()
+			a.pos = p.pos
+		case !p.done() && p.peek() == '(':
+			p.pos = start
+			c, err := p.call()
+			if err != nil {
+				return a, err
+			}
+
+			end := p.pos
+			p.skipSpace()
+			if p.done() || p.peek() == ',' || p.peek() == ')' {
+				a.call = &c
+				a.value = p.src[start:end]
+				return a, nil
+			}
+
+			// not only a call, eg, f(x) + 1, so it is bare text
+			p.pos = start
+		default:
+			p.pos = start
+		}
+	}
+
+	if !p.done() && (p.peek() == '\'' || p.peek() == '"') {
+		s, err := p.quoted()
+		if err != nil {
+			return a, err
+		}
+		a.value = s
+		return a, nil
+	}
+
+	// bare text runs to a comma or closing parenthesis outside of any
+	// parentheses, brackets or braces it opens
+	depth := 0
+	for ; !p.done(); p.pos++ {
+		switch p.peek() {
+		case '(', '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		case ')':
+			if depth == 0 {
+				a.value = strings.TrimSpace(p.src[a.pos:p.pos])
+				return a, nil
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				a.value = strings.TrimSpace(p.src[a.pos:p.pos])
+				return a, nil
+			}
+		}
+	}
+
+	return a, p.errorf(open, "missing )")
+}
+
+// quoted parses a single or double quoted string.
+func (p *tagParser) quoted() (string, error) {
+	quote := p.peek()
+	start := p.pos
+	p.pos++
+
+	var sb strings.Builder
+
+	for !p.done() {
+		switch b := p.peek(); b {
+		case quote:
+			p.pos++
+			return sb.String(), nil
+		case '\\':
+			if p.pos+1 >= len(p.src) {
+				return "", p.errorf(start, "unterminated quoted string")
+			}
+
+			// other escapes, eg, \d in patterns, are kept as they are
+			switch e := p.src[p.pos+1]; e {
+			case '\\', '\'', '"':
+				sb.WriteByte(e)
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(e)
+			}
+			p.pos += 2
+		default:
+			sb.WriteByte(b)
+			p.pos++
+		}
+	}
+
+	return "", p.errorf(start, "unterminated quoted string")
+}
+
+// literal is a value in default(...), either scalar text, a list, eg, [a,b],
+// or a map, eg, {k=v}, whose items may be quoted as in tags.
+type literal struct {
+	kind  literalKind
+	text  string    // scalar text
+	keys  []string  // map keys
+	items []literal // list items or map values
+}
+
+type literalKind int
+
+const (
+	literalScalar literalKind = iota
+	literalList
+	literalMap
+)
+
+// parseLiteral parses a list or map literal, or scalar text.
+func parseLiteral(src string) (literal, error) {
+	p := &tagParser{src: src}
+
+	l, err := p.literal()
+	if err != nil {
+		return l, err
+	}
+
+	p.skipSpace()
+	if !p.done() {
+		return l, p.errorf(p.pos, "expected the end but found %q", p.peek())
+	}
+
+	return l, nil
+}
+
+func (p *tagParser) literal() (literal, error) {
+	p.skipSpace()
+
+	if p.done() {
+		return literal{}, p.errorf(p.pos, "expected a value but found the end")
+	}
+
+	switch p.peek() {
+	case '[':
+		l := literal{kind: literalList}
+		err := p.literalItems(']', func() error {
+			item, err := p.literal()
+			l.items = append(l.items, item)
+			return err
+		})
+		return l, err
+	case '{':
+		l := literal{kind: literalMap}
+		err := p.literalItems('}', func() error {
+			key, err := p.literalKey()
+			if err != nil {
+				return err
+			}
+
+			p.skipSpace()
+			if p.done() || p.peek() != '=' {
+				return p.errorf(p.pos, "expected = after %s", key)
+			}
+			p.pos++
+
+			item, err := p.literal()
+			l.keys = append(l.keys, key)
+			l.items = append(l.items, item)
+			return err
+		})
+		return l, err
+	case '\'', '"':
+		s, err := p.quoted()
+		return literal{text: s}, err
+	}
+
+	// bare text runs to a comma or closing bracket or brace
+	start := p.pos
+	for !p.done() && !strings.ContainsRune(",]}", rune(p.peek())) {
+		p.pos++
+	}
+
+	text := strings.TrimSpace(p.src[start:p.pos])
+	if text == "" {
+		return literal{}, p.errorf(start, "expected a value")
+	}
+
+	return literal{text: text}, nil
+}
+
+// literalItems parses the items of a list or map up to and including its
+// closing bracket or brace, calling item for each.
+func (p *tagParser) literalItems(close byte, item func() error) error {
+	open := p.pos
+	p.pos++
+
+	p.skipSpace()
+	if !p.done() && p.peek() == close {
+		p.pos++
+		return nil
+	}
+
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+
+		p.skipSpace()
+		if p.done() {
+			return p.errorf(open, "missing %c", close)
+		}
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case close:
+			p.pos++
+			return nil
+		default:
+			return p.errorf(p.pos, "expected , or %c but found %q", close, p.peek())
+		}
+	}
+}
+
+// literalKey parses a quoted or bare map key.
+func (p *tagParser) literalKey() (string, error) {
+	p.skipSpace()
+
+	if !p.done() && (p.peek() == '\'' || p.peek() == '"') {
+		return p.quoted()
+	}
+
+	start := p.pos
+	for !p.done() && !strings.ContainsRune("=,}", rune(p.peek())) {
+		p.pos++
+	}
+
+	key := strings.TrimSpace(p.src[start:p.pos])
+	if key == "" {
+		return "", p.errorf(start, "expected a key")
+	}
+
+	return key, nil
+}
+
+// quoteLiteral returns s as a literal item, quoted only if needed.
+func quoteLiteral(s string) string {
+	if s != "" && s == strings.TrimSpace(s) && !strings.ContainsAny(s, `,()[]{}'"\=`) {
+		return s
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// structTagEntry is one key:"value" in a struct tag.
+type structTagEntry struct {
+	key      string
+	value    string
+	unquoted bool // eg, version:1, which older models used
+}
+
+// parseStructTag parses the conventional key:"value" struct tag syntax,
+// returning the entries up to the first problem, where
+// reflect.StructTag.Lookup silently stops reading. Unquoted values, eg,
+// version:1, run to the next space and are deprecated.
+func parseStructTag(tag string) ([]structTagEntry, error) {
+	entries := []structTagEntry{}
+	i := 0
+
+	for i < len(tag) {
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+
+		start := i
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+
+		if i == start || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] <= ' ' {
+			return entries, &tagSyntaxError{src: tag, offset: start, msg: `expected key:"value"`}
+		}
+
+		name := tag[start:i]
+		i++
+
+		if tag[i] != '"' {
+			vstart := i
+			for i < len(tag) && tag[i] != ' ' {
+				i++
+			}
+
+			entries = append(entries, structTagEntry{key: name, value: tag[vstart:i], unquoted: true})
+			continue
+		}
+
+		qstart := i
+		i++
+
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+
+		if i >= len(tag) {
+			return entries, &tagSyntaxError{src: tag, offset: qstart, msg: fmt.Sprintf("unterminated value for %s", name)}
+		}
+
+		i++
+
+		v, err := strconv.Unquote(tag[qstart:i])
+		if err != nil {
+			return entries, &tagSyntaxError{src: tag, offset: qstart, msg: fmt.Sprintf("invalid value for %s: %s", name, err)}
+		}
+
+		entries = append(entries, structTagEntry{key: name, value: v})
+	}
+
+	return entries, nil
+}
+
+// checkStructTag reports problems with the struct tag of the field at
+// fieldPath: an error for broken syntax and a warning for each deprecated
+// unquoted value.
+func checkStructTag(tag, fieldPath string, diags *diag.Diagnostics) {
+	entries, err := parseStructTag(tag)
+	if err != nil {
+		addFieldError(diags, fieldPath, err.Error())
+	}
+
+	for _, e := range entries {
+		if e.unquoted {
+			diags.AddWarning(fmt.Sprintf("Deprecated %s tag on field %s", e.key, fieldPath), fmt.Sprintf("unquoted tag values are deprecated, use %s:%q", e.key, e.value))
+		}
+	}
+}
+
+// tagValue returns the value of key in tags.
+func tagValue(key string, tags string) string {
+	entries, _ := parseStructTag(tags)
+
+	for _, e := range entries {
+		if e.key == key {
+			return e.value
+		}
+	}
+
+	return ""
+}
+
+// tagValueOr returns the value of key from tags or, if not set there, from
+// defaults.
+func tagValueOr(key, tags, defaults string) string {
+	if v := tagValue(key, tags); v != "" {
+		return v
+	}
+
+	return tagValue(key, defaults)
+}
+
+// tagCalls parses the value of key in tags, reporting problems against the
+// field at fieldPath.
+func tagCalls(key, tags, fieldPath string, diags *diag.Diagnostics) []tagCall {
+	calls, err := parseTagValue(tagValue(key, tags))
+	if err != nil {
+		addTagError(diags, fieldPath, key, err.Error())
+		return nil
+	}
+
+	return calls
+}
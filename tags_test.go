@@ -0,0 +1,331 @@
+package mdlschm
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+func TestParseTagValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value   string
+		want    []string
+		wantErr string
+	}{
+		"Empty": {
+			value: ``,
+			want:  []string{},
+		},
+		"Basic": {
+			value: `between(3,32)`,
+			want: []string{
+				"between(3,32)",
+			},
+		},
+		"Test2": {
+			value: `between(3,32),arbitrary(5,2,3,1)`,
+			want: []string{
+				"between(3,32)",
+				"arbitrary(5,2,3,1)",
+			},
+		},
+		"Test3": {
+			value: `fred,any(5),toast(1,8),between(3,32),arbitrary(5,2,3,1)`,
+			want: []string{
+				"fred",
+				"any(5)",
+				"toast(1,8)",
+				"between(3,32)",
+				"arbitrary(5,2,3,1)",
+			},
+		},
+		"Spaces": {
+			value: ` replace , between( 3, 32 ) `,
+			want: []string{
+				"replace",
+				"between(3,32)",
+			},
+		},
+		"Quoted": {
+			value: `oneof('a, b',"c)",'it\'s',"back\\slash")`,
+			want: []string{
+				`oneof('a, b','c)','it\'s','back\\slash')`,
+			},
+		},
+		"BareParens": {
+			value: `default(fn(x) + 1)`,
+			want: []string{
+				"default('fn(x) + 1')",
+			},
+		},
+		"Nested": {
+			value: `each(between(1,3),oneof(a,b)),keys(noneof(x))`,
+			want: []string{
+				"each(between(1,3),oneof(a,b))",
+				"keys(noneof(x))",
+			},
+		},
+		"KeyValue": {
+			value: `default(env=REGION),replace(if = set)`,
+			want: []string{
+				"default(env=REGION)",
+				"replace(if=set)",
+			},
+		},
+		"EmptyArgs": {
+			value: `default(),default('')`,
+			want: []string{
+				"default()",
+				"default('')",
+			},
+		},
+		"Unterminated": {
+			value:   `oneof(a,'b)`,
+			wantErr: `unterminated quoted string at column 9 of oneof(a,'b)`,
+		},
+		"MissingParen": {
+			value:   `replace,between(1,3`,
+			wantErr: `missing ) at column 16 of replace,between(1,3`,
+		},
+		"TrailingComma": {
+			value:   `replace,`,
+			wantErr: `expected a name but found the end at column 9 of replace,`,
+		},
+		"Space": {
+			value:   `replace usfu`,
+			wantErr: `expected , but found 'u' at column 9 of replace usfu`,
+		},
+		"AfterQuote": {
+			value:   `oneof('a'b)`,
+			wantErr: `expected , or ) but found 'b' at column 10 of oneof('a'b)`,
+		},
+		"OtherEscapes": {
+			value: `regex('^\d+\.\d+$')`,
+			want: []string{
+				`regex('^\\d+\\.\\d+$')`,
+			},
+		},
+		"Brackets": {
+			value: `default([a, b]),default({k=v,'x'=[1]})`,
+			want: []string{
+				"default('[a, b]')",
+				"default('{k=v,\\'x\\'=[1]}')",
+			},
+		},
+		"StrayParen": {
+			value:   `replace)`,
+			wantErr: `expected , but found ')' at column 8 of replace)`,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			calls, err := parseTagValue(test.value)
+
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Errorf("unexpected error:\ngot %v\nexpected %s", err, test.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got := []string{}
+			for _, c := range calls {
+				got = append(got, c.String())
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unexpected difference:\ngot %+v\nexpected %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseLiteral(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value   string
+		want    literal
+		wantErr string
+	}{
+		"Scalar": {
+			value: ` a b `,
+			want:  literal{text: "a b"},
+		},
+		"List": {
+			value: `[a, 'b, c', "d]"]`,
+			want: literal{kind: literalList, items: []literal{
+				{text: "a"},
+				{text: "b, c"},
+				{text: "d]"},
+			}},
+		},
+		"Empty": {
+			value: `[ ]`,
+			want:  literal{kind: literalList},
+		},
+		"Map": {
+			value: `{env = dev, 'team name'=[x]}`,
+			want: literal{kind: literalMap, keys: []string{"env", "team name"}, items: []literal{
+				{text: "dev"},
+				{kind: literalList, items: []literal{{text: "x"}}},
+			}},
+		},
+		"MissingBracket": {
+			value:   `[a,b`,
+			wantErr: `missing ] at column 1 of [a,b`,
+		},
+		"MissingValue": {
+			value:   `[a,,b]`,
+			wantErr: `expected a value at column 4 of [a,,b]`,
+		},
+		"MissingEquals": {
+			value:   `{a}`,
+			wantErr: `expected = after a at column 3 of {a}`,
+		},
+		"Trailing": {
+			value:   `[a]b`,
+			wantErr: `expected the end but found 'b' at column 4 of [a]b`,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseLiteral(test.value)
+
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Errorf("unexpected error:\ngot %v\nexpected %s", err, test.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unexpected difference:\ngot %+v\nexpected %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTagValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		tags string
+		key  string
+		want string
+	}{
+		"Basic": {
+			tags: `tfsdk:"name" required:"true" valid:"between(3,32)"`,
+			key:  "valid",
+			want: "between(3,32)",
+		},
+		"Spaces": {
+			tags: `tfsdk:"name" md:"This is a description with spaces" valid:"between( 3, 32 )"`,
+			key:  "md",
+			want: "This is a description with spaces",
+		},
+		"Colon": {
+			tags: `tfsdk:"arn" pmods:"default(arn:aws:iam::aws:policy)"`,
+			key:  "pmods",
+			want: "default(arn:aws:iam::aws:policy)",
+		},
+		"Escaped": {
+			tags: `desc:"Say \"hi\""`,
+			key:  "desc",
+			want: `Say "hi"`,
+		},
+		"Missing": {
+			tags: `tfsdk:"name"`,
+			key:  "desc",
+			want: "",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tagValue(test.key, test.tags)
+
+			if got != test.want {
+				t.Errorf("unexpected difference:\ngot %+v\nexpected %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckStructTag(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		tags string
+		want []string
+	}{
+		"Good": {
+			tags: `tfsdk:"name" required:"true" desc:"Say \"hi\""`,
+			want: []string{},
+		},
+		"Unquoted": {
+			tags: `tfsdk:"name" version:1 desc:"A description"`,
+			want: []string{
+				`Deprecated version tag on field Name: unquoted tag values are deprecated, use version:"1"`,
+			},
+		},
+		"Missing": {
+			tags: `tfsdk:"name" version: desc:"A description"`,
+			want: []string{
+				`Invalid field Name: expected key:"value" at column 14 of tfsdk:"name" version: desc:"A description"`,
+			},
+		},
+		"Unterminated": {
+			tags: `tfsdk:"name" desc:"A description`,
+			want: []string{
+				`Invalid field Name: unterminated value for desc at column 19 of tfsdk:"name" desc:"A description`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var diags diag.Diagnostics
+			checkStructTag(test.tags, "Name", &diags)
+
+			got := []string{}
+			for _, d := range diags {
+				got = append(got, fmt.Sprintf("%s: %s", d.Summary(), d.Detail()))
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unexpected difference:\ngot %+v\nexpected %+v", got, test.want)
+			}
+		})
+	}
+}
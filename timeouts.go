@@ -0,0 +1,238 @@
+package mdlschm
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+const (
+	// Tag keys
+	TagTimeouts = "timeouts"
+
+	// Tag Values
+	TagTimeoutsCreate = "create"
+	TagTimeoutsRead   = "read"
+	TagTimeoutsUpdate = "update"
+	TagTimeoutsDelete = "delete"
+)
+
+// timeoutsOperations are the operations a timeouts block can have, in the
+// order they are written in tags.
+var timeoutsOperations = []string{TagTimeoutsCreate, TagTimeoutsRead, TagTimeoutsUpdate, TagTimeoutsDelete}
+
+// Timeouts holds a timeouts block, with a duration string, eg, 30m, for each
+// operation. A model field of type *Timeouts becomes a single nested block
+// with an optional attribute for each operation in its timeouts tag, eg,
+// timeouts:"create,delete", or for all of them if there is no tag. Timeouts
+// converts itself to and from Terraform values so the framework can get and
+// set it whichever operations the block has.
+type Timeouts struct {
+	value tftypes.Value
+}
+
+var (
+	_ tftypes.ValueConverter = (*Timeouts)(nil)
+	_ tftypes.ValueCreator   = (*Timeouts)(nil)
+)
+
+// FromTerraform5Value is called by the framework to set the block from
+// configuration, plan or state.
+func (t *Timeouts) FromTerraform5Value(v tftypes.Value) error {
+	if !v.Type().Is(tftypes.Object{}) {
+		return fmt.Errorf("timeouts must be an object, got %s", v.Type())
+	}
+
+	t.value = v
+
+	return nil
+}
+
+// ToTerraform5Value is called by the framework to get the block. A nil or
+// zero Timeouts is no block.
+func (t *Timeouts) ToTerraform5Value() (any, error) {
+	if t == nil || t.value.IsNull() {
+		return nil, nil
+	}
+
+	if !t.value.IsKnown() {
+		return tftypes.UnknownValue, nil
+	}
+
+	m := map[string]tftypes.Value{}
+	if err := t.value.As(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Create returns the create timeout or, if it is not set, fallback.
+func (t *Timeouts) Create(fallback time.Duration) time.Duration {
+	return t.duration(TagTimeoutsCreate, fallback)
+}
+
+// Read returns the read timeout or, if it is not set, fallback.
+func (t *Timeouts) Read(fallback time.Duration) time.Duration {
+	return t.duration(TagTimeoutsRead, fallback)
+}
+
+// Update returns the update timeout or, if it is not set, fallback.
+func (t *Timeouts) Update(fallback time.Duration) time.Duration {
+	return t.duration(TagTimeoutsUpdate, fallback)
+}
+
+// Delete returns the delete timeout or, if it is not set, fallback.
+func (t *Timeouts) Delete(fallback time.Duration) time.Duration {
+	return t.duration(TagTimeoutsDelete, fallback)
+}
+
+// duration returns the timeout for operation or fallback if there is no
+// block, the block has no such operation or its value is null, unknown or,
+// despite the validators, not a duration.
+func (t *Timeouts) duration(operation string, fallback time.Duration) time.Duration {
+	if t == nil || t.value.IsNull() || !t.value.IsKnown() {
+		return fallback
+	}
+
+	m := map[string]tftypes.Value{}
+	if err := t.value.As(&m); err != nil {
+		return fallback
+	}
+
+	v, ok := m[operation]
+	if !ok || v.IsNull() || !v.IsKnown() {
+		return fallback
+	}
+
+	var s string
+	if err := v.As(&s); err != nil {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return fallback
+	}
+
+	return d
+}
+
+// isTimeoutsOperation reports whether name is one of timeoutsOperations.
+func isTimeoutsOperation(name string) bool {
+	for _, op := range timeoutsOperations {
+		if op == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTimeouts reports whether t is Timeouts or *Timeouts.
+func isTimeouts(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t == reflect.TypeOf(Timeouts{})
+}
+
+// timeoutsField returns the block for a Timeouts field. The bool reports
+// whether the field is a Timeouts field or has a timeouts tag, even if there
+// were problems.
+func timeoutsField(f reflect.StructField, fieldPath string, diags *diag.Diagnostics) (*tfsdk.Block, bool) {
+	tags := string(f.Tag)
+	_, tagged := f.Tag.Lookup(TagTimeouts)
+
+	if !isTimeouts(f.Type) {
+		if tagged {
+			addTagError(diags, fieldPath, TagTimeouts, fmt.Sprintf("%s is only for *mdlschm.Timeouts fields, not %s", TagTimeouts, f.Type))
+			return nil, true
+		}
+
+		return nil, false
+	}
+
+	if f.Type.Kind() != reflect.Pointer {
+		addFieldError(diags, fieldPath, "use *mdlschm.Timeouts so the framework can set the timeouts block")
+		return nil, true
+	}
+
+	operations := timeoutsOperations
+
+	if calls := tagCalls(TagTimeouts, tags, fieldPath, diags); len(calls) > 0 {
+		operations = []string{}
+		seen := make(map[string]bool)
+
+		for _, c := range calls {
+			switch {
+			case c.hasArgs:
+				addTagError(diags, fieldPath, TagTimeouts, fmt.Sprintf("%s takes no arguments, at column %d", c.name, c.pos+1))
+			case !isTimeoutsOperation(c.name):
+				addTagError(diags, fieldPath, TagTimeouts, fmt.Sprintf("unrecognized operation %s, at column %d, expected %s, %s, %s or %s", c.name, c.pos+1, TagTimeoutsCreate, TagTimeoutsRead, TagTimeoutsUpdate, TagTimeoutsDelete))
+			case seen[c.name]:
+				addTagError(diags, fieldPath, TagTimeouts, fmt.Sprintf("%s is repeated, at column %d", c.name, c.pos+1))
+			default:
+				seen[c.name] = true
+				operations = append(operations, c.name)
+			}
+		}
+	}
+
+	b := timeoutsBlock(operations)
+	addBlockOptions(b, false, true, tags, fieldPath, diags)
+
+	return b, true
+}
+
+// timeoutsBlock returns a single nested block with an optional duration
+// attribute for each operation.
+func timeoutsBlock(operations []string) *tfsdk.Block {
+	attrs := make(map[string]tfsdk.Attribute)
+
+	for _, op := range operations {
+		attrs[op] = tfsdk.Attribute{
+			Type:     types.StringType,
+			Optional: true,
+			Validators: []tfsdk.AttributeValidator{
+				StringIsDuration(),
+			},
+		}
+	}
+
+	return &tfsdk.Block{
+		Attributes:  attrs,
+		NestingMode: tfsdk.BlockNestingModeSingle,
+	}
+}
+
+// blockTimeouts returns the operations of a block that timeoutsBlock could
+// have made, in tag order, and whether it could.
+func blockTimeouts(b tfsdk.Block) ([]string, bool) {
+	operations := []string{}
+
+	for _, op := range timeoutsOperations {
+		if _, ok := b.Attributes[op]; ok {
+			operations = append(operations, op)
+		}
+	}
+
+	if len(operations) == 0 || len(operations) != len(b.Attributes) || len(b.Validators) > 0 {
+		return nil, false
+	}
+
+	want := timeoutsBlock(operations)
+	want.Description = b.Description
+	want.MarkdownDescription = b.MarkdownDescription
+	want.DeprecationMessage = b.DeprecationMessage
+	want.PlanModifiers = b.PlanModifiers
+	want.Validators = b.Validators
+
+	return operations, reflect.DeepEqual(*want, b)
+}
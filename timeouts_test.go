@@ -0,0 +1,246 @@
+package mdlschm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type testTimeoutsModel struct {
+	Name     types.String `tfsdk:"name" required:"true"`
+	Timeouts *Timeouts    `tfsdk:"timeouts" timeouts:"create,delete"`
+}
+
+func TestTimeoutsSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		model any
+		want  []string
+	}{
+		"Tag": {
+			model: testTimeoutsModel{},
+			want:  []string{TagTimeoutsCreate, TagTimeoutsDelete},
+		},
+		"NoTag": {
+			model: struct {
+				Timeouts *Timeouts `desc:"How long to wait"`
+			}{},
+			want: []string{TagTimeoutsCreate, TagTimeoutsRead, TagTimeoutsUpdate, TagTimeoutsDelete},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schm, diags := NewE(test.model)
+			if diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+
+			b, ok := schm.Blocks["timeouts"]
+			if !ok {
+				t.Fatalf("no timeouts block: %+v", schm)
+			}
+
+			if b.NestingMode != tfsdk.BlockNestingModeSingle {
+				t.Errorf("got nesting mode %v, want single", b.NestingMode)
+			}
+
+			got, ok := blockTimeouts(b)
+			if !ok {
+				t.Errorf("not a timeouts block: %+v", b)
+			}
+
+			if diff := deep.Equal(got, test.want); diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, test.want, diff)
+			}
+		})
+	}
+}
+
+func TestTimeoutsErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		model any
+		want  []string
+	}{
+		"NotPointer": {
+			model: struct {
+				Timeouts Timeouts `timeouts:"create"`
+			}{},
+			want: []string{
+				"Invalid field Timeouts",
+			},
+		},
+		"NotTimeouts": {
+			model: struct {
+				Timeouts types.Object `timeouts:"create"`
+			}{},
+			want: []string{
+				"Invalid timeouts tag on field Timeouts",
+			},
+		},
+		"Operations": {
+			model: struct {
+				Timeouts *Timeouts `timeouts:"create,destroy,create,delete(1h)"`
+			}{},
+			want: []string{
+				"Invalid timeouts tag on field Timeouts",
+				"Invalid timeouts tag on field Timeouts",
+				"Invalid timeouts tag on field Timeouts",
+			},
+		},
+		"Syntax": {
+			model: struct {
+				Timeouts *Timeouts `timeouts:"create,"`
+			}{},
+			want: []string{
+				"Invalid timeouts tag on field Timeouts",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, diags := NewE(test.model)
+
+			got := []string{}
+			for _, d := range diags.Errors() {
+				got = append(got, d.Summary())
+			}
+
+			if diff := deep.Equal(got, test.want); diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, test.want, diff)
+			}
+		})
+	}
+}
+
+func TestTimeoutsDurations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	schm := New(testTimeoutsModel{})
+	tt := schm.Type().TerraformType(ctx)
+	ot := schm.Blocks["timeouts"].Type().TerraformType(ctx)
+
+	tests := map[string]struct {
+		timeouts   tftypes.Value
+		wantCreate time.Duration
+		wantRead   time.Duration
+		wantDelete time.Duration
+	}{
+		"Set": {
+			timeouts: tftypes.NewValue(ot, map[string]tftypes.Value{
+				"create": tftypes.NewValue(tftypes.String, "45m"),
+				"delete": tftypes.NewValue(tftypes.String, "1h30m"),
+			}),
+			wantCreate: 45 * time.Minute,
+			wantRead:   5 * time.Minute,
+			wantDelete: 90 * time.Minute,
+		},
+		"PartlySet": {
+			timeouts: tftypes.NewValue(ot, map[string]tftypes.Value{
+				"create": tftypes.NewValue(tftypes.String, nil),
+				"delete": tftypes.NewValue(tftypes.String, "2h"),
+			}),
+			wantCreate: 20 * time.Minute,
+			wantRead:   5 * time.Minute,
+			wantDelete: 2 * time.Hour,
+		},
+		"NoBlock": {
+			timeouts:   tftypes.NewValue(ot, nil),
+			wantCreate: 20 * time.Minute,
+			wantRead:   5 * time.Minute,
+			wantDelete: 10 * time.Minute,
+		},
+		"Unknown": {
+			timeouts:   tftypes.NewValue(ot, tftypes.UnknownValue),
+			wantCreate: 20 * time.Minute,
+			wantRead:   5 * time.Minute,
+			wantDelete: 10 * time.Minute,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			raw := tftypes.NewValue(tt, map[string]tftypes.Value{
+				"name":     tftypes.NewValue(tftypes.String, "web"),
+				"timeouts": test.timeouts,
+			})
+
+			var model testTimeoutsModel
+			if diags := (tfsdk.Plan{Schema: schm, Raw: raw}).Get(ctx, &model); diags.HasError() {
+				t.Fatalf("unexpected errors getting plan: %v", diags)
+			}
+
+			if got := model.Timeouts.Create(20 * time.Minute); got != test.wantCreate {
+				t.Errorf("got create %s, want %s", got, test.wantCreate)
+			}
+
+			if got := model.Timeouts.Read(5 * time.Minute); got != test.wantRead {
+				t.Errorf("got read %s, want %s", got, test.wantRead)
+			}
+
+			if got := model.Timeouts.Delete(10 * time.Minute); got != test.wantDelete {
+				t.Errorf("got delete %s, want %s", got, test.wantDelete)
+			}
+
+			// the block goes back into state as it came
+			state := tfsdk.State{Schema: schm, Raw: tftypes.NewValue(tt, nil)}
+			if diags := state.Set(ctx, &model); diags.HasError() {
+				t.Fatalf("unexpected errors setting state: %v", diags)
+			}
+
+			if !state.Raw.Equal(raw) {
+				t.Errorf("got state %s, want %s", state.Raw, raw)
+			}
+		})
+	}
+}
+
+func TestTimeoutsNil(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	schm := New(testTimeoutsModel{})
+
+	model := testTimeoutsModel{Name: types.String{Value: "web"}}
+
+	if got := model.Timeouts.Update(time.Minute); got != time.Minute {
+		t.Errorf("got update %s, want %s", got, time.Minute)
+	}
+
+	state := tfsdk.State{Schema: schm, Raw: tftypes.NewValue(schm.Type().TerraformType(ctx), nil)}
+	if diags := state.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("unexpected errors setting state: %v", diags)
+	}
+
+	var timeouts types.Object
+	if diags := state.GetAttribute(ctx, path.Root("timeouts"), &timeouts); diags.HasError() {
+		t.Fatalf("unexpected errors getting timeouts: %v", diags)
+	}
+
+	if !timeouts.IsNull() {
+		t.Errorf("got timeouts %s, want null", timeouts)
+	}
+}
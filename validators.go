@@ -0,0 +1,161 @@
+package mdlschm
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// stringCheckValidator checks that a string attribute starts with, ends with
+// or contains Value, depending on Check, the name of the valid tag that makes
+// it.
+type stringCheckValidator struct {
+	Check string
+	Value string
+}
+
+// StringHasPrefix returns a validator that checks that a string starts with
+// prefix.
+func StringHasPrefix(prefix string) tfsdk.AttributeValidator {
+	return stringCheckValidator{Check: TagValidatorPrefix, Value: prefix}
+}
+
+// StringHasSuffix returns a validator that checks that a string ends with
+// suffix.
+func StringHasSuffix(suffix string) tfsdk.AttributeValidator {
+	return stringCheckValidator{Check: TagValidatorSuffix, Value: suffix}
+}
+
+// StringContains returns a validator that checks that a string contains
+// substr.
+func StringContains(substr string) tfsdk.AttributeValidator {
+	return stringCheckValidator{Check: TagValidatorContains, Value: substr}
+}
+
+var _ tfsdk.AttributeValidator = stringCheckValidator{}
+
+func (v stringCheckValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v stringCheckValidator) MarkdownDescription(_ context.Context) string {
+	switch v.Check {
+	case TagValidatorPrefix:
+		return fmt.Sprintf("value must start with %q", v.Value)
+	case TagValidatorSuffix:
+		return fmt.Sprintf("value must end with %q", v.Value)
+	}
+
+	return fmt.Sprintf("value must contain %q", v.Value)
+}
+
+func (v stringCheckValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, res *tfsdk.ValidateAttributeResponse) {
+	// custom string types are checked by their Terraform value
+	tv, err := req.AttributeConfig.ToTerraformValue(ctx)
+	if err != nil {
+		res.Diagnostics.AddAttributeError(req.AttributePath, "Invalid value", err.Error())
+		return
+	}
+
+	if !tv.IsKnown() || tv.IsNull() {
+		return
+	}
+
+	var s string
+	if err := tv.As(&s); err != nil {
+		res.Diagnostics.Append(validatordiag.InvalidAttributeTypeDiagnostic(req.AttributePath, "expected value of type string", tv.Type().String()))
+		return
+	}
+
+	ok := false
+	switch v.Check {
+	case TagValidatorPrefix:
+		ok = strings.HasPrefix(s, v.Value)
+	case TagValidatorSuffix:
+		ok = strings.HasSuffix(s, v.Value)
+	default:
+		ok = strings.Contains(s, v.Value)
+	}
+
+	if !ok {
+		res.Diagnostics.Append(validatordiag.InvalidAttributeValueMatchDiagnostic(req.AttributePath, v.Description(ctx), s))
+	}
+}
+
+// formatValidator checks that a string attribute is in Format, the name of
+// the valid tag that makes it: a Go duration, eg, 30s or 1h30m, an RFC3339
+// timestamp or base64 encoded data.
+type formatValidator struct {
+	Format string
+}
+
+// StringIsDuration returns a validator that checks that a string is a
+// duration, eg, 30s, 10m or 2h45m.
+func StringIsDuration() tfsdk.AttributeValidator {
+	return formatValidator{Format: TagValidatorDuration}
+}
+
+// StringIsRFC3339 returns a validator that checks that a string is an
+// RFC3339 timestamp, eg, 2006-01-02T15:04:05Z.
+func StringIsRFC3339() tfsdk.AttributeValidator {
+	return formatValidator{Format: TagValidatorRFC3339}
+}
+
+// StringIsBase64 returns a validator that checks that a string is standard
+// base64 encoded data.
+func StringIsBase64() tfsdk.AttributeValidator {
+	return formatValidator{Format: TagValidatorBase64}
+}
+
+var _ tfsdk.AttributeValidator = formatValidator{}
+
+func (v formatValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v formatValidator) MarkdownDescription(_ context.Context) string {
+	switch v.Format {
+	case TagValidatorRFC3339:
+		return "value must be an RFC3339 timestamp, eg, 2006-01-02T15:04:05Z"
+	case TagValidatorBase64:
+		return "value must be base64 encoded"
+	}
+
+	return "value must be a duration, eg, 30s, 10m or 2h45m"
+}
+
+func (v formatValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, res *tfsdk.ValidateAttributeResponse) {
+	tv, err := req.AttributeConfig.ToTerraformValue(ctx)
+	if err != nil {
+		res.Diagnostics.AddAttributeError(req.AttributePath, "Invalid value", err.Error())
+		return
+	}
+
+	if !tv.IsKnown() || tv.IsNull() {
+		return
+	}
+
+	var s string
+	if err := tv.As(&s); err != nil {
+		res.Diagnostics.Append(validatordiag.InvalidAttributeTypeDiagnostic(req.AttributePath, "expected value of type string", tv.Type().String()))
+		return
+	}
+
+	switch v.Format {
+	case TagValidatorRFC3339:
+		_, err = time.Parse(time.RFC3339, s)
+	case TagValidatorBase64:
+		_, err = base64.StdEncoding.DecodeString(s)
+	default:
+		_, err = time.ParseDuration(s)
+	}
+
+	if err != nil {
+		res.Diagnostics.Append(validatordiag.InvalidAttributeValueDiagnostic(req.AttributePath, v.Description(ctx), s))
+	}
+}
@@ -0,0 +1,171 @@
+package mdlschm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestStringCheckValidator(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		validator tfsdk.AttributeValidator
+		value     attr.Value
+		wantError bool
+	}{
+		"Prefix": {
+			validator: StringHasPrefix("arn:"),
+			value:     types.String{Value: "arn:aws:iam::aws:policy"},
+		},
+		"PrefixMissing": {
+			validator: StringHasPrefix("arn:"),
+			value:     types.String{Value: "aws:iam"},
+			wantError: true,
+		},
+		"Suffix": {
+			validator: StringHasSuffix(".com"),
+			value:     types.String{Value: "example.com"},
+		},
+		"SuffixMissing": {
+			validator: StringHasSuffix(".com"),
+			value:     types.String{Value: "example.org"},
+			wantError: true,
+		},
+		"Contains": {
+			validator: StringContains(":iam:"),
+			value:     types.String{Value: "arn:aws:iam::aws:policy"},
+		},
+		"ContainsMissing": {
+			validator: StringContains(":iam:"),
+			value:     types.String{Value: "arn:aws:s3:::bucket"},
+			wantError: true,
+		},
+		"Null": {
+			validator: StringHasPrefix("arn:"),
+			value:     types.String{Null: true},
+		},
+		"Unknown": {
+			validator: StringHasPrefix("arn:"),
+			value:     types.String{Unknown: true},
+		},
+		"CustomType": {
+			validator: StringHasPrefix("arn:"),
+			value:     testARN{Value: types.String{Value: "nope"}},
+			wantError: true,
+		},
+		"NotString": {
+			validator: StringContains("1"),
+			value:     types.Int64{Value: 1},
+			wantError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := tfsdk.ValidateAttributeRequest{
+				AttributePath:   path.Root("test"),
+				AttributeConfig: test.value,
+			}
+			res := tfsdk.ValidateAttributeResponse{}
+
+			test.validator.Validate(context.Background(), req, &res)
+
+			if res.Diagnostics.HasError() != test.wantError {
+				t.Errorf("got errors %v, want errors %t", res.Diagnostics, test.wantError)
+			}
+		})
+	}
+}
+
+func TestFormatValidator(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		validator tfsdk.AttributeValidator
+		value     attr.Value
+		wantError bool
+	}{
+		"Minutes": {
+			validator: StringIsDuration(),
+			value:     types.String{Value: "10m"},
+		},
+		"Compound": {
+			validator: StringIsDuration(),
+			value:     types.String{Value: "2h45m"},
+		},
+		"NoUnit": {
+			validator: StringIsDuration(),
+			value:     types.String{Value: "10"},
+			wantError: true,
+		},
+		"Words": {
+			validator: StringIsDuration(),
+			value:     types.String{Value: "ten minutes"},
+			wantError: true,
+		},
+		"Timestamp": {
+			validator: StringIsRFC3339(),
+			value:     types.String{Value: "2022-10-05T17:30:00Z"},
+		},
+		"TimestampOffset": {
+			validator: StringIsRFC3339(),
+			value:     types.String{Value: "2022-10-05T10:30:00.5-07:00"},
+		},
+		"TimestampNoZone": {
+			validator: StringIsRFC3339(),
+			value:     types.String{Value: "2022-10-05T17:30:00"},
+			wantError: true,
+		},
+		"Base64": {
+			validator: StringIsBase64(),
+			value:     types.String{Value: "aGVsbG8="},
+		},
+		"Base64Unpadded": {
+			validator: StringIsBase64(),
+			value:     types.String{Value: "aGVsbG8"},
+			wantError: true,
+		},
+		"Null": {
+			validator: StringIsDuration(),
+			value:     types.String{Null: true},
+		},
+		"Unknown": {
+			validator: StringIsRFC3339(),
+			value:     types.String{Unknown: true},
+		},
+		"NotString": {
+			validator: StringIsBase64(),
+			value:     types.Int64{Value: 10},
+			wantError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := tfsdk.ValidateAttributeRequest{
+				AttributePath:   path.Root("test"),
+				AttributeConfig: test.value,
+			}
+			res := tfsdk.ValidateAttributeResponse{}
+
+			test.validator.Validate(context.Background(), req, &res)
+
+			if res.Diagnostics.HasError() != test.wantError {
+				t.Errorf("got errors %v, want errors %t", res.Diagnostics, test.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,548 @@
+package mdlschm
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// StringFromDuration returns d as a string value, eg, 1h30m0s, for a
+// time.Duration field.
+func StringFromDuration(d time.Duration) types.String {
+	return types.String{Value: d.String()}
+}
+
+// DurationFromString returns the duration in a string value, eg, 90m, for a
+// time.Duration field. Null and unknown values are 0.
+func DurationFromString(v types.String) (time.Duration, error) {
+	if v.IsNull() || v.IsUnknown() {
+		return 0, nil
+	}
+
+	return time.ParseDuration(v.Value)
+}
+
+// StringFromTime returns t as an RFC3339 string value, with fractional
+// seconds if it has them, for a time.Time field.
+func StringFromTime(t time.Time) types.String {
+	return types.String{Value: t.Format(time.RFC3339Nano)}
+}
+
+// TimeFromString returns the time in an RFC3339 string value for a time.Time
+// field. Null and unknown values are the zero time.
+func TimeFromString(v types.String) (time.Time, error) {
+	if v.IsNull() || v.IsUnknown() {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.RFC3339, v.Value)
+}
+
+// StringFromBytes returns b as a standard base64 string value for a []byte
+// field. Nil is null.
+func StringFromBytes(b []byte) types.String {
+	if b == nil {
+		return types.String{Null: true}
+	}
+
+	return types.String{Value: base64.StdEncoding.EncodeToString(b)}
+}
+
+// BytesFromString returns the data in a standard base64 string value for a
+// []byte field. Null and unknown values are nil.
+func BytesFromString(v types.String) ([]byte, error) {
+	if v.IsNull() || v.IsUnknown() {
+		return nil, nil
+	}
+
+	return base64.StdEncoding.DecodeString(v.Value)
+}
+
+// Decode sets target, a pointer to a model struct, from a tfsdk.Config,
+// tfsdk.Plan or tfsdk.State, or a pointer to one. Unlike their Get methods,
+// Decode understands every field type New does, eg, string, *int32,
+// []string, map[string]bool and time.Duration, as well as framework types,
+// eg, types.String, and *Timeouts. A struct field, a list block of at most
+// one, is its block or the zero value if there is none. Null and unknown
+// values are zero values, or nil for pointers, slices and maps. Problems, eg,
+// a value out of range for an int8, are reported at the attribute's path.
+func Decode(ctx context.Context, from any, target any) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	raw, schm, ok := rawSchema(from)
+	if !ok {
+		diags.AddError("Invalid source", fmt.Sprintf("expected tfsdk.Config, tfsdk.Plan or tfsdk.State, got %T", from))
+		return diags
+	}
+
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		diags.AddError("Invalid model", fmt.Sprintf("expected pointer to struct, got %T", target))
+		return diags
+	}
+
+	decodeValue(ctx, raw, schm.Type(), v.Elem(), path.Empty(), &diags)
+
+	return diags
+}
+
+// Encode sets the raw value of state, using its schema, from model, a model
+// struct or a pointer to one, with the same field types as Decode. Nil
+// pointers, slices and maps are null, and a struct field is a block of one.
+// Attributes without a field are null.
+func Encode(ctx context.Context, model any, state *tfsdk.State) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	v := reflect.ValueOf(model)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		diags.AddError("Invalid model", fmt.Sprintf("expected struct, got %T", model))
+		return diags
+	}
+
+	raw := encodeValue(ctx, v, state.Schema.Type(), path.Empty(), &diags)
+	if diags.HasError() {
+		return diags
+	}
+
+	state.Raw = raw
+
+	return diags
+}
+
+// rawSchema returns the raw value and schema of a tfsdk.Config, tfsdk.Plan or
+// tfsdk.State, or a pointer to one.
+func rawSchema(from any) (tftypes.Value, tfsdk.Schema, bool) {
+	switch f := from.(type) {
+	case tfsdk.Config:
+		return f.Raw, f.Schema, true
+	case *tfsdk.Config:
+		return f.Raw, f.Schema, f != nil
+	case tfsdk.Plan:
+		return f.Raw, f.Schema, true
+	case *tfsdk.Plan:
+		return f.Raw, f.Schema, f != nil
+	case tfsdk.State:
+		return f.Raw, f.Schema, true
+	case *tfsdk.State:
+		return f.Raw, f.Schema, f != nil
+	}
+
+	return tftypes.Value{}, tfsdk.Schema{}, false
+}
+
+// attrValueType is the type of attr.Value, which framework types implement.
+var attrValueType = reflect.TypeOf((*attr.Value)(nil)).Elem()
+
+// decodeValue sets target from v, a value of type t. It follows the order of
+// rFields and leaf: Timeouts, framework values, then Go types by kind.
+func decodeValue(ctx context.Context, v tftypes.Value, t attr.Type, target reflect.Value, p path.Path, diags *diag.Diagnostics) {
+	goType := target.Type()
+
+	switch {
+	case isTimeouts(goType) && goType.Kind() == reflect.Pointer:
+		if v.IsNull() {
+			target.Set(reflect.Zero(goType))
+			return
+		}
+
+		to := &Timeouts{}
+		if err := to.FromTerraform5Value(v); err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+		target.Set(reflect.ValueOf(to))
+		return
+	case goType.Kind() != reflect.Pointer && goType.Implements(attrValueType):
+		av, err := t.ValueFromTerraform(ctx, v)
+		if err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+
+		if !reflect.TypeOf(av).AssignableTo(goType) {
+			addValueError(diags, p, fmt.Sprintf("cannot set %s from %T", goType, av))
+			return
+		}
+		target.Set(reflect.ValueOf(av))
+		return
+	}
+
+	if v.IsNull() || !v.IsKnown() {
+		target.Set(reflect.Zero(goType))
+		return
+	}
+
+	switch goType {
+	case durationType, timeType, bytesType:
+		var s string
+		if err := v.As(&s); err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+
+		var got any
+		var err error
+
+		switch goType {
+		case durationType:
+			got, err = DurationFromString(types.String{Value: s})
+		case timeType:
+			got, err = TimeFromString(types.String{Value: s})
+		default:
+			got, err = BytesFromString(types.String{Value: s})
+		}
+
+		if err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+		target.Set(reflect.ValueOf(got))
+		return
+	}
+
+	switch goType.Kind() {
+	case reflect.Pointer:
+		e := reflect.New(goType.Elem())
+		decodeValue(ctx, v, t, e.Elem(), p, diags)
+		target.Set(e)
+	case reflect.Bool:
+		var b bool
+		if err := v.As(&b); err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+		target.SetBool(b)
+	case reflect.String:
+		var s string
+		if err := v.As(&s); err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+		target.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		var n big.Float
+		if err := v.As(&n); err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+		decodeNumber(&n, target, p, diags)
+	case reflect.Slice:
+		var elems []tftypes.Value
+		if err := v.As(&elems); err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+
+		et := collectionElemType(t)
+		s := reflect.MakeSlice(goType, len(elems), len(elems))
+
+		for i, ev := range elems {
+			ep := p.AtListIndex(i)
+			if _, ok := t.(types.SetType); ok {
+				ep = setElemPath(ctx, p, et, ev)
+			}
+			decodeValue(ctx, ev, et, s.Index(i), ep, diags)
+		}
+		target.Set(s)
+	case reflect.Map:
+		var elems map[string]tftypes.Value
+		if err := v.As(&elems); err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+
+		et := collectionElemType(t)
+		m := reflect.MakeMapWithSize(goType, len(elems))
+
+		for k, ev := range elems {
+			e := reflect.New(goType.Elem()).Elem()
+			decodeValue(ctx, ev, et, e, p.AtMapKey(k), diags)
+			m.SetMapIndex(reflect.ValueOf(k).Convert(goType.Key()), e)
+		}
+		target.Set(m)
+	case reflect.Struct:
+		if et, ok := blockElemType(t); ok {
+			// a struct field is a list or set block of at most one
+			var elems []tftypes.Value
+			if err := v.As(&elems); err != nil {
+				addValueError(diags, p, err.Error())
+				return
+			}
+
+			switch len(elems) {
+			case 0:
+				target.Set(reflect.Zero(goType))
+			case 1:
+				ep := p.AtListIndex(0)
+				if _, ok := t.(types.SetType); ok {
+					ep = setElemPath(ctx, p, et, elems[0])
+				}
+				decodeValue(ctx, elems[0], et, target, ep, diags)
+			default:
+				addValueError(diags, p, fmt.Sprintf("cannot set %s from %d blocks", goType, len(elems)))
+			}
+			return
+		}
+
+		ot, ok := t.(types.ObjectType)
+		if !ok {
+			addValueError(diags, p, fmt.Sprintf("cannot set %s from %s", goType, t))
+			return
+		}
+
+		var attrs map[string]tftypes.Value
+		if err := v.As(&attrs); err != nil {
+			addValueError(diags, p, err.Error())
+			return
+		}
+
+		for _, f := range modelFields(goType) {
+			name := schemaName(f)
+			if name == "" {
+				continue
+			}
+
+			at, ok := ot.AttrTypes[name]
+			if !ok {
+				addValueError(diags, p, fmt.Sprintf("field %s has no attribute %s in the schema", f.fieldPath, name))
+				continue
+			}
+
+			av, ok := attrs[name]
+			if !ok {
+				av = tftypes.NewValue(at.TerraformType(ctx), nil)
+			}
+
+			decodeValue(ctx, av, at, target.FieldByIndex(f.Index), p.AtName(name), diags)
+		}
+	default:
+		addValueError(diags, p, fmt.Sprintf("cannot set %s", goType))
+	}
+}
+
+// decodeNumber sets target, of a number kind, from n, reporting values that
+// do not fit.
+func decodeNumber(n *big.Float, target reflect.Value, p path.Path, diags *diag.Diagnostics) {
+	switch target.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f, _ := n.Float64()
+		if target.OverflowFloat(f) {
+			addValueError(diags, p, fmt.Sprintf("%s is out of range for %s", n.String(), target.Type()))
+			return
+		}
+		target.SetFloat(f)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, acc := n.Uint64()
+		if !n.IsInt() || acc != big.Exact || target.OverflowUint(u) {
+			addValueError(diags, p, fmt.Sprintf("%s is not a whole number in range for %s", n.String(), target.Type()))
+			return
+		}
+		target.SetUint(u)
+	default:
+		i, acc := n.Int64()
+		if !n.IsInt() || acc != big.Exact || target.OverflowInt(i) {
+			addValueError(diags, p, fmt.Sprintf("%s is not a whole number in range for %s", n.String(), target.Type()))
+			return
+		}
+		target.SetInt(i)
+	}
+}
+
+// encodeValue returns the value of type t for source, the reverse of
+// decodeValue.
+func encodeValue(ctx context.Context, source reflect.Value, t attr.Type, p path.Path, diags *diag.Diagnostics) tftypes.Value {
+	tt := t.TerraformType(ctx)
+	null := tftypes.NewValue(tt, nil)
+	goType := source.Type()
+
+	switch {
+	case isTimeouts(goType) && goType.Kind() == reflect.Pointer:
+		got, err := source.Interface().(*Timeouts).ToTerraform5Value()
+		if err != nil {
+			addValueError(diags, p, err.Error())
+			return null
+		}
+		return checkedValue(tt, got, p, diags)
+	case goType.Kind() != reflect.Pointer && goType.Implements(attrValueType):
+		tv, err := source.Interface().(attr.Value).ToTerraformValue(ctx)
+		if err != nil {
+			addValueError(diags, p, err.Error())
+			return null
+		}
+
+		if !tv.Type().Equal(tt) {
+			addValueError(diags, p, fmt.Sprintf("got a value of type %s, want %s", tv.Type(), tt))
+			return null
+		}
+		return tv
+	}
+
+	switch goType {
+	case durationType, timeType, bytesType:
+		var s types.String
+
+		switch goType {
+		case durationType:
+			s = StringFromDuration(time.Duration(source.Int()))
+		case timeType:
+			s = StringFromTime(source.Interface().(time.Time))
+		default:
+			s = StringFromBytes(source.Bytes())
+		}
+
+		tv, _ := s.ToTerraformValue(ctx)
+		return tv
+	}
+
+	switch goType.Kind() {
+	case reflect.Pointer:
+		if source.IsNil() {
+			return null
+		}
+		return encodeValue(ctx, source.Elem(), t, p, diags)
+	case reflect.Bool:
+		return checkedValue(tt, source.Bool(), p, diags)
+	case reflect.String:
+		return checkedValue(tt, source.String(), p, diags)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return checkedValue(tt, new(big.Float).SetInt64(source.Int()), p, diags)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return checkedValue(tt, new(big.Float).SetUint64(source.Uint()), p, diags)
+	case reflect.Float32, reflect.Float64:
+		f := source.Float()
+		if math.IsNaN(f) {
+			addValueError(diags, p, "NaN is not a number Terraform can store")
+			return null
+		}
+		return checkedValue(tt, big.NewFloat(f), p, diags)
+	case reflect.Slice:
+		if source.IsNil() {
+			return null
+		}
+
+		et := collectionElemType(t)
+		elems := make([]tftypes.Value, source.Len())
+
+		for i := range elems {
+			elems[i] = encodeValue(ctx, source.Index(i), et, p.AtListIndex(i), diags)
+		}
+		return checkedValue(tt, elems, p, diags)
+	case reflect.Map:
+		if source.IsNil() {
+			return null
+		}
+
+		et := collectionElemType(t)
+		elems := make(map[string]tftypes.Value, source.Len())
+
+		iter := source.MapRange()
+		for iter.Next() {
+			k := iter.Key().String()
+			elems[k] = encodeValue(ctx, iter.Value(), et, p.AtMapKey(k), diags)
+		}
+		return checkedValue(tt, elems, p, diags)
+	case reflect.Struct:
+		if et, ok := blockElemType(t); ok {
+			// a struct field is a list or set block of one
+			ev := encodeValue(ctx, source, et, p.AtListIndex(0), diags)
+			return checkedValue(tt, []tftypes.Value{ev}, p, diags)
+		}
+
+		ot, ok := t.(types.ObjectType)
+		if !ok {
+			addValueError(diags, p, fmt.Sprintf("cannot get %s from %s", t, goType))
+			return null
+		}
+
+		attrs := make(map[string]tftypes.Value, len(ot.AttrTypes))
+
+		for _, f := range modelFields(goType) {
+			name := schemaName(f)
+			if name == "" {
+				continue
+			}
+
+			at, ok := ot.AttrTypes[name]
+			if !ok {
+				addValueError(diags, p, fmt.Sprintf("field %s has no attribute %s in the schema", f.fieldPath, name))
+				continue
+			}
+
+			attrs[name] = encodeValue(ctx, source.FieldByIndex(f.Index), at, p.AtName(name), diags)
+		}
+
+		for name, at := range ot.AttrTypes {
+			if _, ok := attrs[name]; !ok {
+				attrs[name] = tftypes.NewValue(at.TerraformType(ctx), nil)
+			}
+		}
+		return checkedValue(tt, attrs, p, diags)
+	}
+
+	addValueError(diags, p, fmt.Sprintf("cannot get a value from %s", goType))
+	return null
+}
+
+// checkedValue returns a new value of type tt, reporting, rather than
+// panicking on, a Go value or elements that do not fit it.
+func checkedValue(tt tftypes.Type, v any, p path.Path, diags *diag.Diagnostics) tftypes.Value {
+	if err := tftypes.ValidateValue(tt, v); err != nil {
+		addValueError(diags, p, err.Error())
+		return tftypes.NewValue(tt, nil)
+	}
+
+	return tftypes.NewValue(tt, v)
+}
+
+// blockElemType returns the object type of a list or set of objects, the
+// type of the list block New makes from a struct field.
+func blockElemType(t attr.Type) (attr.Type, bool) {
+	switch t.(type) {
+	case types.ListType, types.SetType:
+		if et, ok := collectionElemType(t).(types.ObjectType); ok {
+			return et, true
+		}
+	}
+
+	return nil, false
+}
+
+// setElemPath returns the path of a set element, which is the element's
+// value, or the set's path if the element cannot be a value.
+func setElemPath(ctx context.Context, p path.Path, et attr.Type, ev tftypes.Value) path.Path {
+	av, err := et.ValueFromTerraform(ctx, ev)
+	if err != nil {
+		return p
+	}
+
+	return p.AtSetValue(av)
+}
+
+// schemaName returns the attribute or block name of a model field, or "" if
+// the field is skipped. Problems with the name are left to NewE.
+func schemaName(f modelField) string {
+	var discard diag.Diagnostics
+	return attrName(f.Name, string(f.Tag), f.fieldPath, &discard)
+}
+
+// addValueError reports a value that cannot be converted at an attribute path.
+func addValueError(diags *diag.Diagnostics, p path.Path, detail string) {
+	diags.AddAttributeError(p, "Invalid value", detail)
+}
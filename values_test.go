@@ -0,0 +1,540 @@
+package mdlschm
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDurationFromString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value   types.String
+		want    time.Duration
+		wantErr bool
+	}{
+		"Duration": {
+			value: types.String{Value: "1h30m"},
+			want:  90 * time.Minute,
+		},
+		"Null": {
+			value: types.String{Null: true},
+		},
+		"Unknown": {
+			value: types.String{Unknown: true},
+		},
+		"Invalid": {
+			value:   types.String{Value: "90"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DurationFromString(test.value)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+
+			if got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+
+			if test.wantErr || test.value.IsNull() || test.value.IsUnknown() {
+				return
+			}
+
+			if back, _ := DurationFromString(StringFromDuration(got)); back != got {
+				t.Errorf("round trip got %s, want %s", back, got)
+			}
+		})
+	}
+}
+
+func TestTimeFromString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value   types.String
+		want    time.Time
+		wantErr bool
+	}{
+		"UTC": {
+			value: types.String{Value: "2022-10-05T17:30:00Z"},
+			want:  time.Date(2022, 10, 5, 17, 30, 0, 0, time.UTC),
+		},
+		"FractionalSeconds": {
+			value: types.String{Value: "2022-10-05T17:30:00.25Z"},
+			want:  time.Date(2022, 10, 5, 17, 30, 0, 250000000, time.UTC),
+		},
+		"Null": {
+			value: types.String{Null: true},
+		},
+		"Invalid": {
+			value:   types.String{Value: "2022-10-05 17:30"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := TimeFromString(test.value)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+
+			if !got.Equal(test.want) {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+
+			if test.wantErr || test.value.IsNull() {
+				return
+			}
+
+			if s := StringFromTime(got); !s.Equal(test.value) {
+				t.Errorf("round trip got %s, want %s", s, test.value)
+			}
+		})
+	}
+}
+
+func TestBytesFromString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		value   types.String
+		want    []byte
+		wantErr bool
+	}{
+		"Data": {
+			value: types.String{Value: "aGVsbG8="},
+			want:  []byte("hello"),
+		},
+		"Empty": {
+			value: types.String{Value: ""},
+			want:  []byte{},
+		},
+		"Null": {
+			value: types.String{Null: true},
+		},
+		"Invalid": {
+			value:   types.String{Value: "not base64!"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := BytesFromString(test.value)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+
+			if test.wantErr {
+				return
+			}
+
+			if diff := deep.Equal(got, test.want); diff != nil {
+				t.Errorf("got: %v\nwant: %v\ndifference: %v", got, test.want, diff)
+			}
+
+			if s := StringFromBytes(got); !s.Equal(test.value) {
+				t.Errorf("round trip got %s, want %s", s, test.value)
+			}
+		})
+	}
+}
+
+type testNativeRule struct {
+	Priority int8   `required:"true"`
+	Action   string `optional:"true"`
+}
+
+type testNativeLimit struct {
+	Max *int32 `optional:"true"`
+}
+
+type testNativeModel struct {
+	Name     string            `required:"true"`
+	Enabled  *bool             `optional:"true"`
+	Port     uint16            `optional:"true"`
+	Weight   float32           `optional:"true"`
+	Tags     map[string]string `optional:"true"`
+	Aliases  []string          `optional:"true"`
+	Zones    []string          `optional:"true" collection:"set"`
+	Interval time.Duration     `optional:"true"`
+	Expires  *time.Time        `optional:"true"`
+	Data     []byte            `optional:"true"`
+	Note     types.String      `optional:"true"`
+	Rule     []testNativeRule
+	Limit    testNativeLimit
+	Timeouts *Timeouts `timeouts:"create"`
+}
+
+// testNativeValues returns the raw values of a testNativeModel, all set or
+// all null or unknown, with overrides.
+func testNativeValues(ctx context.Context, schm tfsdk.Schema, set, known bool, overrides map[string]tftypes.Value) tftypes.Value {
+	ot := schm.Type().TerraformType(ctx).(tftypes.Object)
+
+	attrs := map[string]tftypes.Value{}
+	for name, t := range ot.AttributeTypes {
+		switch {
+		case !known:
+			attrs[name] = tftypes.NewValue(t, tftypes.UnknownValue)
+		default:
+			attrs[name] = tftypes.NewValue(t, nil)
+		}
+	}
+
+	if set {
+		rt := ot.AttributeTypes["rule"].(tftypes.List).ElementType
+		lt := ot.AttributeTypes["limit"].(tftypes.List).ElementType
+		strs := func(ss ...string) []tftypes.Value {
+			vals := []tftypes.Value{}
+			for _, s := range ss {
+				vals = append(vals, tftypes.NewValue(tftypes.String, s))
+			}
+			return vals
+		}
+
+		attrs["name"] = tftypes.NewValue(tftypes.String, "web")
+		attrs["enabled"] = tftypes.NewValue(tftypes.Bool, true)
+		attrs["port"] = tftypes.NewValue(tftypes.Number, big.NewFloat(8080))
+		attrs["weight"] = tftypes.NewValue(tftypes.Number, big.NewFloat(0.5))
+		attrs["tags"] = tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{"env": tftypes.NewValue(tftypes.String, "prod")})
+		attrs["aliases"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, strs("www", "app"))
+		attrs["zones"] = tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, strs("a"))
+		attrs["interval"] = tftypes.NewValue(tftypes.String, "1h30m0s")
+		attrs["expires"] = tftypes.NewValue(tftypes.String, "2022-10-05T17:30:00Z")
+		attrs["data"] = tftypes.NewValue(tftypes.String, "aGVsbG8=")
+		attrs["note"] = tftypes.NewValue(tftypes.String, "hi")
+		attrs["rule"] = tftypes.NewValue(ot.AttributeTypes["rule"], []tftypes.Value{
+			tftypes.NewValue(rt, map[string]tftypes.Value{
+				"priority": tftypes.NewValue(tftypes.Number, big.NewFloat(-1)),
+				"action":   tftypes.NewValue(tftypes.String, "allow"),
+			}),
+		})
+		attrs["limit"] = tftypes.NewValue(ot.AttributeTypes["limit"], []tftypes.Value{
+			tftypes.NewValue(lt, map[string]tftypes.Value{
+				"max": tftypes.NewValue(tftypes.Number, big.NewFloat(10)),
+			}),
+		})
+		attrs["timeouts"] = tftypes.NewValue(ot.AttributeTypes["timeouts"], map[string]tftypes.Value{
+			"create": tftypes.NewValue(tftypes.String, "45m"),
+		})
+	}
+
+	for name, v := range overrides {
+		attrs[name] = v
+	}
+
+	return tftypes.NewValue(ot, attrs)
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	schm := New(testNativeModel{})
+	ot := schm.Type().TerraformType(ctx).(tftypes.Object)
+
+	enabled := true
+	max := int32(10)
+	expires := time.Date(2022, 10, 5, 17, 30, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		raw        tftypes.Value
+		want       testNativeModel
+		wantCreate time.Duration
+	}{
+		"Set": {
+			raw:        testNativeValues(ctx, schm, true, true, nil),
+			wantCreate: 45 * time.Minute,
+			want: testNativeModel{
+				Name:     "web",
+				Enabled:  &enabled,
+				Port:     8080,
+				Weight:   0.5,
+				Tags:     map[string]string{"env": "prod"},
+				Aliases:  []string{"www", "app"},
+				Zones:    []string{"a"},
+				Interval: 90 * time.Minute,
+				Expires:  &expires,
+				Data:     []byte("hello"),
+				Note:     types.String{Value: "hi"},
+				Rule:     []testNativeRule{{Priority: -1, Action: "allow"}},
+				Limit:    testNativeLimit{Max: &max},
+			},
+		},
+		"NoBlocks": {
+			raw: testNativeValues(ctx, schm, true, true, map[string]tftypes.Value{
+				"rule":  tftypes.NewValue(ot.AttributeTypes["rule"], []tftypes.Value{}),
+				"limit": tftypes.NewValue(ot.AttributeTypes["limit"], []tftypes.Value{}),
+			}),
+			wantCreate: 45 * time.Minute,
+			want: testNativeModel{
+				Name:     "web",
+				Enabled:  &enabled,
+				Port:     8080,
+				Weight:   0.5,
+				Tags:     map[string]string{"env": "prod"},
+				Aliases:  []string{"www", "app"},
+				Zones:    []string{"a"},
+				Interval: 90 * time.Minute,
+				Expires:  &expires,
+				Data:     []byte("hello"),
+				Note:     types.String{Value: "hi"},
+				Rule:     []testNativeRule{},
+			},
+		},
+		"Null": {
+			raw:        testNativeValues(ctx, schm, false, true, nil),
+			wantCreate: time.Minute,
+			want: testNativeModel{
+				Note: types.String{Null: true},
+			},
+		},
+		"Unknown": {
+			raw:        testNativeValues(ctx, schm, false, false, nil),
+			wantCreate: time.Minute,
+			want: testNativeModel{
+				Note: types.String{Unknown: true},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var got testNativeModel
+			if diags := Decode(ctx, tfsdk.Plan{Schema: schm, Raw: test.raw}, &got); diags.HasError() {
+				t.Fatalf("unexpected errors: %v", diags)
+			}
+
+			if got := got.Timeouts.Create(time.Minute); got != test.wantCreate {
+				t.Errorf("got create timeout %s, want %s", got, test.wantCreate)
+			}
+
+			// Timeouts is compared by its durations
+			got.Timeouts = nil
+
+			if diff := deep.Equal(got, test.want); diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", got, test.want, diff)
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	schm := New(testNativeModel{})
+	ot := schm.Type().TerraformType(ctx).(tftypes.Object)
+	rt := ot.AttributeTypes["rule"].(tftypes.List).ElementType
+	lt := ot.AttributeTypes["limit"].(tftypes.List).ElementType
+
+	tests := map[string]struct {
+		from   any
+		target any
+		want   []string
+	}{
+		"Values": {
+			from: tfsdk.State{Schema: schm, Raw: testNativeValues(ctx, schm, true, true, map[string]tftypes.Value{
+				"port":     tftypes.NewValue(tftypes.Number, big.NewFloat(70000)),
+				"interval": tftypes.NewValue(tftypes.String, "90"),
+				"rule": tftypes.NewValue(ot.AttributeTypes["rule"], []tftypes.Value{
+					tftypes.NewValue(rt, map[string]tftypes.Value{
+						"priority": tftypes.NewValue(tftypes.Number, big.NewFloat(1.5)),
+						"action":   tftypes.NewValue(tftypes.String, nil),
+					}),
+				}),
+				"limit": tftypes.NewValue(ot.AttributeTypes["limit"], []tftypes.Value{
+					tftypes.NewValue(lt, map[string]tftypes.Value{"max": tftypes.NewValue(tftypes.Number, big.NewFloat(1))}),
+					tftypes.NewValue(lt, map[string]tftypes.Value{"max": tftypes.NewValue(tftypes.Number, big.NewFloat(2))}),
+				}),
+			})},
+			target: &testNativeModel{},
+			want: []string{
+				"port",
+				"interval",
+				"rule[0].priority",
+				"limit",
+			},
+		},
+		"NotSource": {
+			from:   tfsdk.Schema{},
+			target: &testNativeModel{},
+			want:   []string{""},
+		},
+		"NotPointer": {
+			from:   &tfsdk.Config{Schema: schm, Raw: testNativeValues(ctx, schm, true, true, nil)},
+			target: testNativeModel{},
+			want:   []string{""},
+		},
+		"NoAttribute": {
+			from: tfsdk.Config{Schema: schm, Raw: testNativeValues(ctx, schm, true, true, nil)},
+			target: &struct {
+				Name  string
+				Extra string
+			}{},
+			want: []string{""},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			diags := Decode(ctx, test.from, test.target)
+
+			if diff := deep.Equal(diagPaths(diags), test.want); diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", diagPaths(diags), test.want, diff)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	schm := New(testNativeModel{})
+	ot := schm.Type().TerraformType(ctx).(tftypes.Object)
+
+	tests := map[string]struct {
+		raw tftypes.Value
+	}{
+		"Set": {
+			raw: testNativeValues(ctx, schm, true, true, nil),
+		},
+		"Null": {
+			// non-pointer Go values have no null
+			raw: testNativeValues(ctx, schm, false, true, map[string]tftypes.Value{
+				"name":     tftypes.NewValue(tftypes.String, ""),
+				"port":     tftypes.NewValue(tftypes.Number, big.NewFloat(0)),
+				"weight":   tftypes.NewValue(tftypes.Number, big.NewFloat(0)),
+				"interval": tftypes.NewValue(tftypes.String, "0s"),
+				"limit": tftypes.NewValue(ot.AttributeTypes["limit"], []tftypes.Value{
+					tftypes.NewValue(ot.AttributeTypes["limit"].(tftypes.List).ElementType, map[string]tftypes.Value{
+						"max": tftypes.NewValue(tftypes.Number, nil),
+					}),
+				}),
+			}),
+		},
+		"UnknownTimeouts": {
+			raw: testNativeValues(ctx, schm, true, true, map[string]tftypes.Value{
+				"timeouts": tftypes.NewValue(ot.AttributeTypes["timeouts"], tftypes.UnknownValue),
+			}),
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var model testNativeModel
+			if diags := Decode(ctx, tfsdk.Plan{Schema: schm, Raw: test.raw}, &model); diags.HasError() {
+				t.Fatalf("unexpected errors decoding: %v", diags)
+			}
+
+			state := tfsdk.State{Schema: schm}
+			if diags := Encode(ctx, &model, &state); diags.HasError() {
+				t.Fatalf("unexpected errors encoding: %v", diags)
+			}
+
+			if !state.Raw.Equal(test.raw) {
+				t.Errorf("got %s, want %s", state.Raw, test.raw)
+			}
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	schm := New(testNativeModel{})
+
+	tests := map[string]struct {
+		model any
+		want  []string
+	}{
+		"NaN": {
+			model: testNativeModel{Weight: float32(math.NaN())},
+			want:  []string{"weight"},
+		},
+		"Mismatch": {
+			model: struct {
+				Name types.Int64
+				Rule []struct {
+					Priority bool
+				}
+			}{
+				Name: types.Int64{Value: 1},
+				Rule: []struct{ Priority bool }{{}},
+			},
+			want: []string{"name", "rule[0].priority"},
+		},
+		"NotStruct": {
+			model: "web",
+			want:  []string{""},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			state := tfsdk.State{Schema: schm}
+			diags := Encode(ctx, test.model, &state)
+
+			if diff := deep.Equal(diagPaths(diags), test.want); diff != nil {
+				t.Errorf("got: %+v\nwant: %+v\ndifference: %v", diagPaths(diags), test.want, diff)
+			}
+		})
+	}
+}
+
+// diagPaths returns the paths of error diagnostics, "" for those without.
+func diagPaths(diags diag.Diagnostics) []string {
+	paths := []string{}
+	for _, d := range diags.Errors() {
+		p := ""
+		if dp, ok := d.(diag.DiagnosticWithPath); ok {
+			p = dp.Path().String()
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}